@@ -0,0 +1,150 @@
+// Package convert 在 pkg/model 和 gRPC 消息 (api/pb) 之间转换
+package convert
+
+import (
+	"time"
+
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+func ResourceToPB(r model.Resource) *pb.Resource {
+	return &pb.Resource{MilliCpu: r.MilliCPU, MemoryBytes: r.Memory}
+}
+
+func ResourceFromPB(r *pb.Resource) model.Resource {
+	return model.Resource{MilliCPU: r.GetMilliCpu(), Memory: r.GetMemoryBytes()}
+}
+
+// JobSpecToPB 转换 Job.Spec
+func JobSpecToPB(job *model.Job) *pb.JobSpec {
+	return &pb.JobSpec{
+		Image:                  job.Spec.Image,
+		Command:                job.Spec.Command,
+		Envs:                   job.Spec.Envs,
+		ImagePullPolicy:        string(job.Spec.ImagePullPolicy),
+		WorkDir:                job.Spec.WorkDir,
+		RetryCount:             int32(job.Spec.RetryCount),
+		RetryBackoffSeconds:    job.Spec.RetryBackoffSeconds,
+		MaxRetryBackoffSeconds: job.Spec.MaxRetryBackoffSeconds,
+		ActiveDeadlineSeconds:  job.Spec.ActiveDeadlineSeconds,
+	}
+}
+
+// JobSpecFromPB 把 spec 填入 job.Spec
+func JobSpecFromPB(spec *pb.JobSpec, job *model.Job) {
+	job.Spec.Image = spec.GetImage()
+	job.Spec.Command = spec.GetCommand()
+	job.Spec.Envs = spec.GetEnvs()
+	job.Spec.ImagePullPolicy = model.PullPolicy(spec.GetImagePullPolicy())
+	job.Spec.WorkDir = spec.GetWorkDir()
+	job.Spec.RetryCount = int(spec.GetRetryCount())
+	job.Spec.RetryBackoffSeconds = spec.GetRetryBackoffSeconds()
+	job.Spec.MaxRetryBackoffSeconds = spec.GetMaxRetryBackoffSeconds()
+	job.Spec.ActiveDeadlineSeconds = spec.GetActiveDeadlineSeconds()
+}
+
+// JobToPB 转换完整的任务 (包括状态)
+func JobToPB(job *model.Job) *pb.Job {
+	status := &pb.JobStatus{
+		State:                 job.Status.State.String(),
+		NodeId:                job.Status.NodeID,
+		Retries:               int32(job.Status.Retries),
+		ExitCode:              int32(job.Status.ExitCode),
+		Error:                 job.Status.Error,
+		Reason:                job.Status.Reason,
+		NextRetryTimeUnixNano: TimeToPB(job.Status.NextRetryTime),
+		StartTimeUnixNano:     TimeToPB(job.Status.StartTime),
+		EndTimeUnixNano:       TimeToPB(job.Status.EndTime),
+		PeakMemoryBytes:       job.Status.PeakMemory,
+		CpuTimeMs:             job.Status.CPUTimeMs,
+	}
+	for _, a := range job.Status.Attempts {
+		status.Attempts = append(status.Attempts, &pb.JobAttempt{
+			NodeId:            a.NodeID,
+			ExitCode:          int32(a.ExitCode),
+			Error:             a.Error,
+			Reason:            a.Reason,
+			StartTimeUnixNano: TimeToPB(a.StartTime),
+			EndTimeUnixNano:   TimeToPB(a.EndTime),
+		})
+	}
+	return &pb.Job{
+		Id:                 job.ID,
+		Name:               job.Name,
+		Type:               string(job.Type),
+		Labels:             job.Labels,
+		Spec:               JobSpecToPB(job),
+		ResReq:             ResourceToPB(job.ResReq),
+		Dependencies:       job.Dependencies,
+		DependencyPolicy:   string(job.DependencyPolicy),
+		CreateTimeUnixNano: TimeToPB(job.CreateTime),
+		Status:             status,
+		ResourceVersion:    job.ResourceVersion,
+	}
+}
+
+// JobFromPB 转换完整的任务，无法识别的状态当作 Pending
+func JobFromPB(j *pb.Job) *model.Job {
+	job := &model.Job{
+		ID:               j.GetId(),
+		Name:             j.GetName(),
+		Type:             model.JobType(j.GetType()),
+		Labels:           j.GetLabels(),
+		CreateTime:       TimeFromPB(j.GetCreateTimeUnixNano()),
+		ResReq:           ResourceFromPB(j.GetResReq()),
+		ResourceVersion:  j.GetResourceVersion(),
+		Dependencies:     j.GetDependencies(),
+		DependencyPolicy: model.DependencyPolicy(j.GetDependencyPolicy()),
+	}
+	JobSpecFromPB(j.GetSpec(), job)
+
+	status := j.GetStatus()
+	job.Status.State, _ = model.ParseJobState(status.GetState())
+	job.Status.NodeID = status.GetNodeId()
+	job.Status.Retries = int(status.GetRetries())
+	job.Status.ExitCode = int(status.GetExitCode())
+	job.Status.Error = status.GetError()
+	job.Status.Reason = status.GetReason()
+	job.Status.NextRetryTime = TimeFromPB(status.GetNextRetryTimeUnixNano())
+	job.Status.StartTime = TimeFromPB(status.GetStartTimeUnixNano())
+	job.Status.EndTime = TimeFromPB(status.GetEndTimeUnixNano())
+	job.Status.PeakMemory = status.GetPeakMemoryBytes()
+	job.Status.CPUTimeMs = status.GetCpuTimeMs()
+	for _, a := range status.GetAttempts() {
+		job.Status.Attempts = append(job.Status.Attempts, model.Attempt{
+			NodeID:    a.GetNodeId(),
+			ExitCode:  int(a.GetExitCode()),
+			Error:     a.GetError(),
+			Reason:    a.GetReason(),
+			StartTime: TimeFromPB(a.GetStartTimeUnixNano()),
+			EndTime:   TimeFromPB(a.GetEndTimeUnixNano()),
+		})
+	}
+	return job
+}
+
+// LogLineToPB 转换一行输出
+func LogLineToPB(line model.LogLine) *pb.LogLine {
+	return &pb.LogLine{TimeUnixNano: TimeToPB(line.Time), Stream: line.Stream, Text: line.Text}
+}
+
+func LogLineFromPB(line *pb.LogLine) model.LogLine {
+	return model.LogLine{Time: TimeFromPB(line.GetTimeUnixNano()), Stream: line.GetStream(), Text: line.GetText()}
+}
+
+// TimeToPB 零值转换为 0
+func TimeToPB(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// TimeFromPB 0 转换为零值
+func TimeFromPB(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
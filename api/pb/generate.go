@@ -0,0 +1,6 @@
+// Package pb 由 api/proto/titan.proto 生成的 gRPC 代码，修改 proto 后在仓库根目录重新生成：
+//
+//	protoc -I . --go_out=. --go_opt=module=titan --go-grpc_out=. --go-grpc_opt=module=titan api/proto/titan.proto
+package pb
+
+//go:generate sh -c "cd ../.. && protoc -I . --go_out=. --go_opt=module=titan --go-grpc_out=. --go-grpc_opt=module=titan api/proto/titan.proto"
@@ -2,16 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"titan/internal/auth"
+	"titan/internal/master/apiserver"
+	"titan/internal/master/dispatcher"
+	"titan/internal/master/nodecontroller"
 	"titan/internal/master/scheduler"
+	"titan/pkg/logstore"
 	"titan/pkg/store"
 )
 
 func main() {
+	grpcAddr := flag.String("grpc-addr", apiserver.DefaultAddr, "Listen address of the gRPC API (used by workers and titan-cli)")
+	httpAddr := flag.String("http-addr", apiserver.DefaultHTTPAddr, "Listen address of the HTTP/JSON gateway (empty = disabled)")
+	flag.Parse()
+
 	// 1. 初始化 Etcd 连接
 	// 假设我们在本地跑 Etcd，端口通常是 2379
 	etcdManager, err := store.NewEtcdManager([]string{"localhost:2379"})
@@ -20,17 +31,60 @@ func main() {
 	}
 	log.Println("Connected to Etcd successfully.")
 
+	// Worker 和 Master 之间用节点 Token 认证，用户用 API Token (见 internal/auth)
+	authCfg := auth.ConfigFromEnv()
+	if authCfg.NodeToken == "" {
+		log.Printf("⚠️ %s is not set: any client can register nodes and report job status", auth.NodeTokenEnv)
+	}
+
 	// 2. 初始化调度器 (依赖注入)
 	sched := scheduler.NewScheduler(etcdManager)
 
+	// 绑定 / 取消任务之后直接通知 Worker (WorkerService)，推送失败时 Worker 通过 Watch 兜底
+	disp := dispatcher.New(etcdManager, authCfg)
+	defer disp.Close()
+	sched.SetDispatcher(disp)
+
 	// 3. 启动调度器 (后台运行)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	go sched.Run(ctx)
 
-	// 4. (未来) 这里还要启动 API Server (HTTP/gRPC) 接收用户请求
-	// go apiServer.Run()
+	// 节点生命周期控制器：心跳超时的节点标记为 OFFLINE
+	nodeCtrl := nodecontroller.NewNodeController(etcdManager)
+	go nodeCtrl.Run(ctx)
+
+	// 4. 启动 gRPC API：Worker 通过它注册节点、发送心跳、上报任务状态 (Master 是集群状态的唯一写入方)
+	//    用户通过 JobService (gRPC 或 HTTP 网关) 提交、查询任务和读取输出
+	//    日志后端和 Worker 保持一致 (TITAN_LOG_BACKEND 等环境变量)
+	logCfg, err := logstore.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid log backend config: %v", err)
+	}
+	logCfg.Token = authCfg.NodeToken // fs 后端：Worker 上的日志读取接口和 WorkerService 使用同一个节点 Token
+	logs, err := logstore.OpenReader(logCfg, etcdManager)
+	if err != nil {
+		log.Fatalf("Failed to open log backend: %v", err)
+	}
+	jobs := apiserver.NewJobService(ctx, etcdManager, logs, authCfg)
+
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", *grpcAddr, err)
+	}
+	go func() {
+		if err := apiserver.Serve(ctx, lis, etcdManager, jobs, authCfg); err != nil {
+			log.Fatalf("gRPC API stopped: %v", err)
+		}
+	}()
+	if *httpAddr != "" {
+		go func() {
+			if err := apiserver.ServeGateway(ctx, *httpAddr, jobs); err != nil {
+				log.Fatalf("HTTP gateway stopped: %v", err)
+			}
+		}()
+	}
 
 	// 5. 优雅退出 (Graceful Shutdown)
 	// 等待 Ctrl+C 信号
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log"
+
+	"google.golang.org/grpc"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+)
+
+// dialMaster 连接 Master 的 JobService
+// Master 要求 Token 时 (TITAN_API_TOKEN)，CLI 从同名环境变量读取并随每个请求发送；
+// Master 使用 TLS 时用 TITAN_TLS_CA 指定校验它的证书用的 CA
+func dialMaster(addr string) (pb.JobServiceClient, *grpc.ClientConn) {
+	cfg := auth.ConfigFromEnv()
+	conn, err := cfg.Dial(addr, cfg.APIToken)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to master: %v", err)
+	}
+	return pb.NewJobServiceClient(conn), conn
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+// listOptions 查询任务列表的命令行参数
+type listOptions struct {
+	states     string
+	node       string
+	namePrefix string
+	labels     string
+	since      time.Duration
+	limit      int
+	continued  string
+}
+
+// runList 按条件查询任务并以表格形式打印
+func runList(client pb.JobServiceClient, opts listOptions) {
+	req, err := opts.toRequest()
+	if err != nil {
+		log.Fatalf("❌ Invalid filter: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := client.ListJobs(ctx, req)
+	if err != nil {
+		log.Fatalf("❌ Failed to list jobs: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATE\tNODE\tCREATED\tRETRIES\tEXIT\tERROR")
+	for _, pj := range list.Jobs {
+		job := convert.JobFromPB(pj)
+		created := "-"
+		if !job.CreateTime.IsZero() {
+			created = job.CreateTime.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%d\t%s\n",
+			job.ID, job.Name, job.Status.State, orDash(job.Status.NodeID), created,
+			job.Status.Retries, job.Spec.RetryCount, job.Status.ExitCode, orDash(job.Status.Error))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d job(s) at revision %d\n", len(list.Jobs), list.Revision)
+	if list.Continue != "" {
+		fmt.Println("💡 More jobs available, fetch the next page with:")
+		fmt.Printf("   go run cmd/titan-cli/main.go -list -limit %d -continue %s\n", opts.limit, list.Continue)
+	}
+}
+
+// toRequest 把命令行参数转换为 ListJobsRequest
+func (o listOptions) toRequest() (*pb.ListJobsRequest, error) {
+	req := &pb.ListJobsRequest{
+		NodeId:     o.node,
+		NamePrefix: o.namePrefix,
+		Limit:      int32(o.limit),
+		Continue:   o.continued,
+	}
+
+	if o.states != "" {
+		for _, name := range strings.Split(o.states, ",") {
+			state, err := model.ParseJobState(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			req.States = append(req.States, state.String())
+		}
+	}
+
+	if o.labels != "" {
+		req.Labels = make(map[string]string)
+		for _, pair := range strings.Split(o.labels, ",") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("label %q must be in key=value form", pair)
+			}
+			req.Labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+
+	if o.since > 0 {
+		req.CreatedAfterUnixNano = time.Now().Add(-o.since).UnixNano()
+	}
+	return req, nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+// runLogs 实现子命令 `titan-cli logs [-f] [-since 10m] [-tail 100] <job-id>`
+//   - 默认打印任务已有的输出
+//   - -f 持续跟随运行中任务的输出，任务结束时打印最终状态，并以任务的结果作为进程退出码
+func runLogs(client pb.JobServiceClient, args []string) {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	follow := fs.Bool("f", false, "Follow the output until the job finishes, then exit with its result")
+	since := fs.Duration("since", 0, "Only show lines written within this duration (e.g. 10m)")
+	tail := fs.Int("tail", -1, "Only show the last N lines of existing output (-1 = all)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titan-cli logs [-f] [-since 10m] [-tail 100] <job-id>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	jobID := fs.Arg(0)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// 历史、日志存储和 Worker 实时输出的合并与去重都在 Master 上完成，这里按收到的顺序打印
+	req := &pb.GetLogsRequest{JobId: jobID, Follow: *follow}
+	if *since > 0 {
+		req.SinceUnixNano = time.Now().Add(-*since).UnixNano()
+	}
+	switch {
+	case *tail > 0:
+		req.Tail = int32(*tail)
+	case *tail == 0:
+		// 不看已有的输出，只看之后的
+		req.SinceUnixNano = time.Now().UnixNano()
+	}
+	if err := printLogs(ctx, client, req); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		log.Fatalf("❌ Failed to get logs: %v", err)
+	}
+	if !*follow {
+		return
+	}
+
+	// 流正常结束说明任务已经结束
+	job, err := client.GetJob(ctx, &pb.GetJobRequest{JobId: jobID})
+	if err != nil {
+		log.Fatalf("❌ Failed to get job: %v", err)
+	}
+	os.Exit(reportFinalStatus(convert.JobFromPB(job)))
+}
+
+// printLogs 打印 GetLogs 推送的输出，直到流结束
+func printLogs(ctx context.Context, client pb.JobServiceClient, req *pb.GetLogsRequest) error {
+	stream, err := client.GetLogs(ctx, req)
+	if err != nil {
+		return err
+	}
+	p := &logPrinter{attempt: -1}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return fmt.Errorf("job %s not found", req.JobId)
+			}
+			return err
+		}
+		for _, line := range resp.Lines {
+			p.printLine(int(resp.Attempt), convert.LogLineFromPB(line))
+		}
+	}
+}
+
+// reportFinalStatus 打印任务的最终状态，返回 CLI 的退出码：
+// Success -> 0；非零退出码失败 -> 任务的退出码；其他失败 (取消、超时、依赖失败等) -> 1
+func reportFinalStatus(job *model.Job) int {
+	fmt.Println("================================================")
+	if job.Status.State == model.JobSuccess {
+		fmt.Printf("✅ Job %s finished: %s\n", job.ID, job.Status.State)
+		return 0
+	}
+	fmt.Printf("❌ Job %s finished: %s", job.ID, job.Status.State)
+	if job.Status.Reason != "" {
+		fmt.Printf(" (%s)", job.Status.Reason)
+	}
+	if job.Status.Error != "" {
+		fmt.Printf(": %s", job.Status.Error)
+	}
+	fmt.Println()
+	if job.Status.ExitCode != 0 {
+		return job.Status.ExitCode
+	}
+	return 1
+}
+
+// logPrinter 按行打印任务输出：时间 + 来源 (stdout/stderr) + 内容
+// 任务重试过时，每次执行的输出之间用分隔行隔开
+type logPrinter struct {
+	attempt int // 上一行属于第几次执行
+}
+
+func (p *logPrinter) printLine(attempt int, line model.LogLine) {
+	if attempt != p.attempt {
+		if p.attempt >= 0 || attempt > 0 {
+			fmt.Printf("---------------- attempt %d ----------------\n", attempt+1)
+		}
+		p.attempt = attempt
+	}
+	fmt.Printf("%s %-6s %s\n", line.Time.Local().Format("15:04:05.000"), line.Stream, line.Text)
+}
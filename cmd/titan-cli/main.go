@@ -8,25 +8,54 @@ import (
 	"sync"
 	"time"
 
+	"titan/api/convert"
+	"titan/api/pb"
 	"titan/pkg/model"
-	"titan/pkg/store"
 )
 
 func main() {
 	// --- 1. 定义命令行参数 ---
+	// Master 的 gRPC 地址 (CLI 只和 Master 通信，不直接访问 Etcd)
+	masterAddr := flag.String("master", "localhost:9090", "Address of the master gRPC API")
 	// 任务数量 (默认 1，想压测可以设为 100, 500...)
 	taskCount := flag.Int("n", 1, "Number of tasks to submit")
 	// 模拟耗时 (默认 1秒，想测长时间任务可以改大)
 	sleepTime := flag.Int("t", 1, "Sleep time in seconds for each task")
+	// 镜像 & 拉取策略 (指定镜像时以 Docker 任务提交)
+	image := flag.String("image", "", "Docker image to run the task in (submits a DOCKER job)")
+	pullPolicy := flag.String("pull", "", "Image pull policy: Always, IfNotPresent or Never")
+	// 最长运行时间 (超时的任务会被杀掉并标记为失败)
+	deadline := flag.Duration("timeout", 0, "Kill the task if it runs longer than this (e.g. 30s, 0 = no limit)")
+	// 失败重试 (次数 & 第一次重试前的等待时间，之后每次翻倍)
+	retries := flag.Int("retries", 0, "Retry a failed task up to this many times")
+	retryBackoff := flag.Duration("retry-backoff", 0, "Wait before the first retry, doubled on each retry (default 10s)")
 	// 获取日志 (如果指定了这个 ID，就不提交任务，只查日志)
 	jobIDToGet := flag.String("getlog", "", "Get logs for a specific Job ID")
+	// 从文件提交一个工作流 (一组有依赖关系的任务)
+	workflowPath := flag.String("f", "", "Submit a workflow (jobs with dependencies) from a JSON file")
+	// 取消任务 (任何状态都可以取消)
+	jobIDToCancel := flag.String("cancel", "", "Cancel a specific Job ID")
+	// 查询任务列表 (支持过滤和分页)
+	list := flag.Bool("list", false, "List jobs instead of submitting")
+	var listOpts listOptions
+	flag.StringVar(&listOpts.states, "state", "", "Filter by state when listing, comma separated (e.g. Pending,Running)")
+	flag.StringVar(&listOpts.node, "node", "", "Filter by node ID when listing")
+	flag.StringVar(&listOpts.namePrefix, "name-prefix", "", "Filter by job name prefix when listing")
+	flag.StringVar(&listOpts.labels, "label", "", "Filter by labels when listing (k=v,k2=v2)")
+	flag.DurationVar(&listOpts.since, "since", 0, "Only list jobs submitted within this duration (e.g. 1h)")
+	flag.IntVar(&listOpts.limit, "limit", 0, "Max number of jobs per page when listing (0 = all)")
+	flag.StringVar(&listOpts.continued, "continue", "", "Continue token returned by the previous page")
 
 	flag.Parse()
 
-	// --- 2. 连接 Etcd ---
-	etcdManager, err := store.NewEtcdManager([]string{"localhost:2379"})
-	if err != nil {
-		log.Fatalf("❌ Failed to connect to etcd: %v", err)
+	// --- 2. 连接 Master ---
+	client, conn := dialMaster(*masterAddr)
+	defer conn.Close()
+
+	// --- 子命令: titan-cli logs [-f] [-since 10m] [-tail 100] <job-id> ---
+	if flag.NArg() > 0 && flag.Arg(0) == "logs" {
+		runLogs(client, flag.Args()[1:])
+		return
 	}
 
 	// --- 3. 分支 A: 查看日志模式 ---
@@ -34,18 +63,44 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		logs, err := etcdManager.GetJobLog(ctx, *jobIDToGet)
-		if err != nil {
-			log.Fatalf("❌ Failed to get logs: %v", err)
-		}
-
 		fmt.Printf("\n📄 Logs for Job [%s]:\n", *jobIDToGet)
 		fmt.Println("================================================")
-		fmt.Println(logs)
+		if err := printLogs(ctx, client, &pb.GetLogsRequest{JobId: *jobIDToGet}); err != nil {
+			log.Fatalf("❌ Failed to get logs: %v", err)
+		}
 		fmt.Println("================================================")
 		return // 查完日志直接结束
 	}
 
+	// --- 分支 E: 提交工作流 ---
+	if *workflowPath != "" {
+		runWorkflow(client, *workflowPath)
+		return
+	}
+
+	// --- 分支 D: 取消任务 ---
+	if *jobIDToCancel != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		resp, err := client.CancelJob(ctx, &pb.CancelJobRequest{JobId: *jobIDToCancel})
+		if err != nil {
+			log.Fatalf("❌ Failed to cancel job: %v", err)
+		}
+		if !resp.Cancelled {
+			fmt.Printf("⚠️  Job %s already finished (%s), nothing to cancel\n", resp.Job.Id, resp.Job.Status.State)
+			return
+		}
+		fmt.Printf("🛑 Job %s cancelled\n", resp.Job.Id)
+		return
+	}
+
+	// --- 分支 C: 查询任务列表 ---
+	if *list {
+		runList(client, listOpts)
+		return
+	}
+
 	// --- 4. 分支 B: 提交任务模式 (支持并发压测) ---
 	fmt.Printf("🚀 Starting submission: %d tasks (Simulating %ds work)...\n", *taskCount, *sleepTime)
 
@@ -76,26 +131,28 @@ func main() {
 				ID:   jobID,
 				Name: fmt.Sprintf("Job-%d", id),
 				Type: model.JobTypeShell,
-				Spec: struct {
-					Image      string   `json:"image,omitempty"`
-					Command    []string `json:"command"`
-					Envs       []string `json:"envs"`
-					RetryCount int      `json:"retry_count"`
-				}{
-					Command: []string{"sh", "-c", cmdStr},
-				},
 				ResReq: model.Resource{
-					MilliCPU: 100,       // 0.1 核
-					Memory:   1024 * 10, // 10 MB
+					MilliCPU: 100,              // 0.1 核
+					Memory:   10 * 1024 * 1024, // 10 MB (字节)
 				},
 			}
-			job.Status.State = model.JobPending
+			job.Spec.Command = []string{"sh", "-c", cmdStr}
+			job.Spec.ActiveDeadlineSeconds = int64(deadline.Seconds())
+			job.Spec.RetryCount = *retries
+			job.Spec.RetryBackoffSeconds = int64(retryBackoff.Seconds())
+			if *image != "" {
+				// 指定了镜像就作为 Docker 任务提交
+				job.Type = model.JobTypeDocker
+				job.Spec.Image = *image
+				job.Spec.ImagePullPolicy = model.PullPolicy(*pullPolicy)
+			}
 
 			// 提交任务
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			if err := etcdManager.CreateJob(ctx, job); err != nil {
+			req := &pb.SubmitJobRequest{Jobs: []*pb.Job{convert.JobToPB(job)}}
+			if _, err := client.SubmitJob(ctx, req); err != nil {
 				fmt.Printf("❌ Failed to submit job %s: %v\n", jobID, err)
 			} else {
 				// 如果是单任务，打印详细点；如果是压测，只打印进度
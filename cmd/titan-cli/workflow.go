@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+// workflowLabel 同一次提交的工作流任务都带上这个标签，方便用 -list -label 查询
+const workflowLabel = "workflow"
+
+// workflowFile 工作流文件 (JSON)：任务格式与 model.Job 相同，通过 dependencies 引用文件内其他任务的 id
+//
+//	{
+//	  "name": "etl",
+//	  "jobs": [
+//	    {"id": "extract", "spec": {"command": ["sh", "-c", "echo extract"]}},
+//	    {"id": "load", "spec": {"command": ["sh", "-c", "echo load"]}, "dependencies": ["extract"]}
+//	  ]
+//	}
+type workflowFile struct {
+	Name string       `json:"name"`
+	Jobs []*model.Job `json:"jobs"`
+}
+
+// runWorkflow 从文件提交整个工作流
+// 文件里的 id 只在文件内有效，提交时加上本次运行的前缀，同一个文件可以反复提交
+func runWorkflow(client pb.JobServiceClient, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("❌ Failed to read workflow file: %v", err)
+	}
+	var wf workflowFile
+	if err := json.Unmarshal(data, &wf); err != nil {
+		log.Fatalf("❌ Invalid workflow file %s: %v", path, err)
+	}
+	if len(wf.Jobs) == 0 {
+		log.Fatalf("❌ Workflow file %s has no jobs", path)
+	}
+
+	name := wf.Name
+	if name == "" {
+		name = "workflow"
+	}
+	// 同一秒内多次提交同一个文件也不能撞 ID，时间戳后面加随机后缀
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		log.Fatalf("❌ Failed to generate workflow run ID: %v", err)
+	}
+	runID := fmt.Sprintf("%s-%d-%s", name, time.Now().Unix(), hex.EncodeToString(suffix))
+	prefixJobIDs(wf.Jobs, runID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 依赖校验和按拓扑序创建由 Master 完成，有问题时整个工作流都不会提交
+	req := &pb.SubmitJobRequest{}
+	for _, job := range wf.Jobs {
+		req.Jobs = append(req.Jobs, convert.JobToPB(job))
+	}
+	resp, err := client.SubmitJob(ctx, req)
+	if err != nil {
+		log.Fatalf("❌ Failed to submit workflow: %v", err)
+	}
+
+	fmt.Printf("✅ Workflow %s submitted (%d jobs)\n", runID, len(resp.Jobs))
+	for _, job := range resp.Jobs {
+		if len(job.Dependencies) > 0 {
+			fmt.Printf("   %s (after %v)\n", job.Id, job.Dependencies)
+		} else {
+			fmt.Printf("   %s\n", job.Id)
+		}
+	}
+	fmt.Println("💡 Track progress with:")
+	fmt.Printf("   go run cmd/titan-cli/main.go -list -label %s=%s\n", workflowLabel, runID)
+}
+
+// prefixJobIDs 把文件内的 id (以及指向它们的依赖) 改写成 "<runID>-<id>"，名字默认使用文件内的 id
+// 其他默认值 (类型、资源需求等) 由 Master 在提交时补齐
+// 依赖里不属于这个文件的 id 保持不变，指向已经提交过的任务
+func prefixJobIDs(jobs []*model.Job, runID string) {
+	local := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		local[job.ID] = true
+	}
+
+	for _, job := range jobs {
+		if job.Name == "" {
+			job.Name = job.ID
+		}
+		if job.ID != "" {
+			job.ID = runID + "-" + job.ID
+		}
+		for i, dep := range job.Dependencies {
+			if local[dep] {
+				job.Dependencies[i] = runID + "-" + dep
+			}
+		}
+		if job.Labels == nil {
+			job.Labels = make(map[string]string)
+		}
+		job.Labels[workflowLabel] = runID
+	}
+}
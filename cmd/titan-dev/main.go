@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/internal/master/apiserver"
+	"titan/internal/master/dispatcher"
+	"titan/internal/master/nodecontroller"
+	"titan/internal/master/scheduler"
+	"titan/internal/worker"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// titan-dev: All-in-one 开发模式
+// Master + Worker 跑在同一个进程里，共享一个内存 Store，不需要启动 Etcd
+func main() {
+	// 启动后自动提交的演示任务数量 (0 表示不提交)
+	taskCount := flag.Int("n", 1, "Number of demo tasks to submit on startup")
+	grpcAddr := flag.String("grpc-addr", "127.0.0.1:9090", "Listen address of the master gRPC API (titan-cli -master)")
+	httpAddr := flag.String("http-addr", "127.0.0.1:8080", "Listen address of the HTTP/JSON gateway (empty = disabled)")
+	flag.Parse()
+
+	// 1. 初始化内存存储 (替代 Etcd)
+	memStore := store.NewMemoryStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Master 和 Worker 在同一个进程里，节点 Token 随机生成即可 (其他本机用户无法冒充节点)
+	// 用户 Token 仍然从 TITAN_API_TOKEN 读取
+	authCfg := auth.ConfigFromEnv()
+	authCfg.NodeToken = rand.Text()
+
+	// 2. 启动调度器 + Worker Agent
+	sched := scheduler.NewScheduler(memStore)
+	disp := dispatcher.New(memStore, authCfg)
+	defer disp.Close()
+	sched.SetDispatcher(disp)
+	go sched.Run(ctx)
+
+	// 节点生命周期控制器：心跳超时的节点标记为 OFFLINE
+	nodeCtrl := nodecontroller.NewNodeController(memStore)
+	go nodeCtrl.Run(ctx)
+
+	// Worker 通过 Master 的 gRPC API 上报状态 (和分布式部署走同一条路径)，titan-cli 也可以连上来
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	jobs := apiserver.NewJobService(ctx, memStore, memStore, authCfg)
+	go func() {
+		if err := apiserver.Serve(ctx, lis, memStore, jobs, authCfg); err != nil {
+			log.Fatalf("gRPC API stopped: %v", err)
+		}
+	}()
+	if *httpAddr != "" {
+		go func() {
+			if err := apiserver.ServeGateway(ctx, *httpAddr, jobs); err != nil {
+				log.Fatalf("HTTP gateway stopped: %v", err)
+			}
+		}()
+	}
+
+	conn, err := authCfg.Dial(lis.Addr().String(), authCfg.NodeToken)
+	if err != nil {
+		log.Fatalf("Failed to connect to master: %v", err)
+	}
+	defer conn.Close()
+
+	// Worker 的 WorkerService 同样只监听本机的随机端口，Master 绑定任务后直接推送过来
+	workerLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	agent := worker.NewAgent(memStore, pb.NewMasterServiceClient(conn), memStore)
+	agent.Auth = authCfg
+	go agent.Run(ctx, workerLis)
+
+	// 3. 提交演示任务
+	for i := 0; i < *taskCount; i++ {
+		job := &model.Job{
+			ID:   fmt.Sprintf("job-%d-%d", time.Now().UnixNano(), i),
+			Name: fmt.Sprintf("Demo-%d", i),
+			Type: model.JobTypeShell,
+			ResReq: model.Resource{
+				MilliCPU: 100,
+				Memory:   10 * 1024 * 1024,
+			},
+		}
+		job.Spec.Command = []string{"sh", "-c", fmt.Sprintf("echo 'Hello from demo task %d'", i)}
+		job.Status.State = model.JobPending
+
+		if err := memStore.CreateJob(ctx, job); err != nil {
+			log.Printf("❌ Failed to submit job %s: %v", job.ID, err)
+			continue
+		}
+		log.Printf("✅ Job submitted! ID: %s", job.ID)
+	}
+
+	// 4. 优雅退出
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down titan-dev...")
+}
@@ -2,30 +2,70 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"titan/api/pb"
+	"titan/internal/auth"
 	"titan/internal/worker"
+	"titan/pkg/logstore"
 	"titan/pkg/store"
 )
 
 func main() {
-	// 1. 连接 Etcd
+	masterAddr := flag.String("master", "localhost:9090", "Address of the master gRPC API")
+	ip := flag.String("ip", "127.0.0.1", "IP registered for this node; the master reaches WorkerService here")
+	port := flag.Int("port", 9092, "Port of the WorkerService gRPC API")
+	flag.Parse()
+
+	// 1. 连接 Etcd (只读：List + Watch 分配给本节点的任务) 和 Master (上报节点和任务状态，使用节点 Token)
 	etcdManager, err := store.NewEtcdManager([]string{"localhost:2379"})
 	if err != nil {
 		log.Fatalf("Failed to connect to etcd: %v", err)
 	}
+	authCfg := auth.ConfigFromEnv()
+	conn, err := authCfg.Dial(*masterAddr, authCfg.NodeToken)
+	if err != nil {
+		log.Fatalf("Failed to connect to master: %v", err)
+	}
+	defer conn.Close()
 
-	// 2. 初始化 Worker Agent
-	agent := worker.NewAgent(etcdManager)
-
-	// 3. 启动 Agent
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go agent.Run(ctx)
+	// 2. 初始化日志后端 (TITAN_LOG_BACKEND，默认和集群状态一起存在 Etcd)
+	logCfg, err := logstore.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid log backend config: %v", err)
+	}
+	logCfg.Token = authCfg.NodeToken // fs 后端：Worker 上的日志读取接口和 WorkerService 使用同一个节点 Token
+	logs, err := logstore.OpenWriter(logCfg, etcdManager)
+	if err != nil {
+		log.Fatalf("Failed to open log backend: %v", err)
+	}
+	if fs, ok := logs.(*logstore.FileStore); ok {
+		// 日志存在本地磁盘，需要提供读取接口给 CLI / Master
+		go func() {
+			if err := fs.Serve(ctx); err != nil {
+				log.Fatalf("Failed to serve logs: %v", err)
+			}
+		}()
+	}
+
+	// 3. 初始化并启动 Worker Agent，Master 通过 WorkerService 推送任务
+	lis, err := net.Listen("tcp", net.JoinHostPort(*ip, strconv.Itoa(*port)))
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	agent := worker.NewAgent(etcdManager, pb.NewMasterServiceClient(conn), logs)
+	agent.IP = *ip
+	agent.Auth = authCfg
+	go agent.Run(ctx, lis)
 
 	// 4. 优雅退出
 	quit := make(chan os.Signal, 1)
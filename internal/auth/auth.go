@@ -0,0 +1,161 @@
+// Package auth gRPC 接口的认证：Bearer Token 和 TLS
+// 用户接口 (JobService) 和集群内部接口 (MasterService / WorkerService) 使用不同的 Token，
+// 拿到用户 Token 的人不能冒充节点上报状态，也不能直接让 Worker 执行任务
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 环境变量
+const (
+	APITokenEnv  = "TITAN_API_TOKEN"  // 用户调用 JobService (gRPC 和 HTTP 网关) 使用的 Token
+	NodeTokenEnv = "TITAN_NODE_TOKEN" // Master 和 Worker 互相调用 (MasterService / WorkerService / fs 日志接口) 使用的 Token
+	TLSCertEnv   = "TITAN_TLS_CERT"   // 服务端证书 (PEM)，和 TLSKeyEnv 一起设置后 gRPC 接口使用 TLS
+	TLSKeyEnv    = "TITAN_TLS_KEY"    // 服务端私钥 (PEM)
+	TLSCAEnv     = "TITAN_TLS_CA"     // 校验对端证书用的 CA (PEM)，设置后客户端使用 TLS 连接
+)
+
+// Config 认证配置，Token 为空表示对应的接口不校验 (只适合开发环境)
+type Config struct {
+	APIToken  string
+	NodeToken string
+
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// ConfigFromEnv 从环境变量读取配置 (见上面的常量)
+func ConfigFromEnv() Config {
+	return Config{
+		APIToken:  os.Getenv(APITokenEnv),
+		NodeToken: os.Getenv(NodeTokenEnv),
+		CertFile:  os.Getenv(TLSCertEnv),
+		KeyFile:   os.Getenv(TLSKeyEnv),
+		CAFile:    os.Getenv(TLSCAEnv),
+	}
+}
+
+// Tokens 每个 gRPC 服务 (如 "titan.JobService") 要求的 Token，空字符串表示不校验
+// 没有列出的服务一律拒绝，新注册的服务必须显式选择认证方式
+type Tokens map[string]string
+
+// ServerOptions 服务端的 TLS (配置了证书时) 和按服务校验 Token 的拦截器
+func (c Config) ServerOptions(tokens Tokens) ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tokens.unaryInterceptor),
+		grpc.ChainStreamInterceptor(tokens.streamInterceptor),
+	}
+	if c.CertFile == "" && c.KeyFile == "" {
+		return opts, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+	creds := credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	return append(opts, grpc.Creds(creds)), nil
+}
+
+// DialOptions 客户端的 TLS (配置了 CA 时) 和随每个请求发送的 Token (为空时不发送)
+func (c Config) DialOptions(token string) ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", c.CAFile)
+		}
+		creds = credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
+	return opts, nil
+}
+
+// Dial 连接 addr 上的 gRPC 服务 (grpc.NewClient 不会立即建立连接，出错只可能是配置不对)
+func (c Config) Dial(addr, token string) (*grpc.ClientConn, error) {
+	opts, err := c.DialOptions(token)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewClient(addr, opts...)
+}
+
+// Authorize 校验 gRPC 请求 (或放进 metadata 的 HTTP 请求头) 携带的 "authorization: Bearer <token>"
+// token 为空时不校验
+func Authorize(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if CheckBearer(md.Get("authorization"), token) {
+		return nil
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid token")
+}
+
+// CheckBearer values 中是否有 "Bearer <token>"
+func CheckBearer(values []string, token string) bool {
+	for _, v := range values {
+		got, ok := strings.CutPrefix(v, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Tokens) authorize(ctx context.Context, fullMethod string) error {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	token, ok := t[service]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "service %s does not accept requests", service)
+	}
+	return Authorize(ctx, token)
+}
+
+func (t Tokens) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := t.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (t Tokens) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := t.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// bearerToken 以 "authorization: Bearer <token>" 发送 Token
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity 没有配置 TLS 时也发送 (Token 以明文传输，生产环境应当同时配置 TLS)
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
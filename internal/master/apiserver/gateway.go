@@ -0,0 +1,306 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/model"
+)
+
+// maxRequestBody 提交任务的请求体上限
+const maxRequestBody = 4 << 20
+
+// 请求和响应都是 protojson，响应的字段名与 titan.proto 一致 (snake_case)
+var marshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// NewGateway JobService 的 HTTP/JSON 网关，直接调用 jobs (不经过 gRPC)
+//
+//	POST /v1/jobs                  提交任务，请求体为 SubmitJobRequest
+//	GET  /v1/jobs                  查询任务，查询参数对应 ListJobsRequest (state / label 可以重复: label=k=v)
+//	GET  /v1/jobs/{id}             任务详情
+//	POST /v1/jobs/{id}/cancel      取消任务
+//	GET  /v1/jobs/{id}/logs        任务输出 (follow / tail / since_unix_nano)，每行一个 GetLogsResponse
+//	GET  /v1/jobs/{id}/watch       任务状态变化，每行一个 Job
+//
+// 流式接口返回 application/x-ndjson；流中途出错时最后一行是 {"error": {...}}
+func NewGateway(jobs *JobService) http.Handler {
+	g := &gateway{jobs: jobs}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/jobs", g.submitJob)
+	mux.HandleFunc("GET /v1/jobs", g.listJobs)
+	mux.HandleFunc("GET /v1/jobs/{id}", g.getJob)
+	mux.HandleFunc("POST /v1/jobs/{id}/cancel", g.cancelJob)
+	mux.HandleFunc("GET /v1/jobs/{id}/logs", g.getLogs)
+	mux.HandleFunc("GET /v1/jobs/{id}/watch", g.watchJob)
+	return g.authenticate(mux)
+}
+
+// ServeGateway 在 addr 上提供 HTTP 网关，直到 ctx 结束
+func ServeGateway(ctx context.Context, addr string, jobs *JobService) error {
+	srv := &http.Server{Addr: addr, Handler: NewGateway(jobs), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("[Master] HTTP gateway listening on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+type gateway struct {
+	jobs *JobService
+}
+
+// authenticate 校验 Authorization 头中的用户 Token (和 gRPC 的 JobService 相同)
+func (g *gateway) authenticate(next http.Handler) http.Handler {
+	token := g.jobs.auth.APIToken
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && !auth.CheckBearer(r.Header.Values("Authorization"), token) {
+			writeError(w, status.Error(codes.Unauthenticated, "missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (g *gateway) submitJob(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+	req := &pb.SubmitJobRequest{}
+	if err := protojson.Unmarshal(body, req); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+		return
+	}
+	resp, err := g.jobs.SubmitJob(r.Context(), req)
+	writeResponse(w, http.StatusCreated, resp, err)
+}
+
+func (g *gateway) listJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &pb.ListJobsRequest{
+		NodeId:     q.Get("node_id"),
+		NamePrefix: q.Get("name_prefix"),
+		Continue:   q.Get("continue"),
+	}
+	for _, v := range q["state"] {
+		req.States = append(req.States, strings.Split(v, ",")...)
+	}
+	for _, v := range q["label"] {
+		k, val, ok := strings.Cut(v, "=")
+		if !ok {
+			writeError(w, status.Errorf(codes.InvalidArgument, "label %q must be in key=value form", v))
+			return
+		}
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		req.Labels[k] = val
+	}
+	var err error
+	if req.CreatedAfterUnixNano, err = queryInt(q.Get("created_after_unix_nano")); err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.CreatedBeforeUnixNano, err = queryInt(q.Get("created_before_unix_nano")); err != nil {
+		writeError(w, err)
+		return
+	}
+	limit, err := queryInt(q.Get("limit"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req.Limit = int32(limit)
+
+	resp, err := g.jobs.ListJobs(r.Context(), req)
+	writeResponse(w, http.StatusOK, resp, err)
+}
+
+func (g *gateway) getJob(w http.ResponseWriter, r *http.Request) {
+	resp, err := g.jobs.GetJob(r.Context(), &pb.GetJobRequest{JobId: r.PathValue("id")})
+	writeResponse(w, http.StatusOK, resp, err)
+}
+
+func (g *gateway) cancelJob(w http.ResponseWriter, r *http.Request) {
+	resp, err := g.jobs.CancelJob(r.Context(), &pb.CancelJobRequest{JobId: r.PathValue("id")})
+	writeResponse(w, http.StatusOK, resp, err)
+}
+
+func (g *gateway) getLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &pb.GetLogsRequest{JobId: r.PathValue("id")}
+	if v := q.Get("follow"); v != "" {
+		follow, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid follow %q", v))
+			return
+		}
+		req.Follow = follow
+	}
+	tail, err := queryInt(q.Get("tail"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req.Tail = int32(tail)
+	if req.SinceUnixNano, err = queryInt(q.Get("since_unix_nano")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s := newNDJSONStream(w)
+	err = g.jobs.streamLogs(r.Context(), req, func(resp *pb.GetLogsResponse) error { return s.send(resp) })
+	s.finish(err)
+}
+
+func (g *gateway) watchJob(w http.ResponseWriter, r *http.Request) {
+	s := newNDJSONStream(w)
+	err := g.jobs.watchJob(r.Context(), r.PathValue("id"), func(job *pb.Job) error { return s.send(job) })
+	s.finish(err)
+}
+
+func queryInt(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid number %q", v)
+	}
+	return n, nil
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp proto.Message, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	data, err := marshalOptions.Marshal(resp)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// errorBody 错误响应：{"error": {"code": "NotFound", "message": "..."}}
+// 校验失败时 fields 列出每个不合法的字段
+type errorBody struct {
+	Error struct {
+		Code    string              `json:"code"`
+		Message string              `json:"message"`
+		Fields  []*model.FieldError `json:"fields,omitempty"`
+	} `json:"error"`
+}
+
+func newErrorBody(err error) errorBody {
+	st := status.Convert(err)
+	var body errorBody
+	body.Error.Code = st.Code().String()
+	body.Error.Message = st.Message()
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				body.Error.Fields = append(body.Error.Fields, &model.FieldError{Field: v.Field, Reason: v.Description})
+			}
+		}
+	}
+	return body
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(status.Code(err)))
+	json.NewEncoder(w).Encode(newErrorBody(err))
+}
+
+// httpStatus gRPC 状态码对应的 HTTP 状态码
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.Canceled:
+		return 499 // 客户端断开
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ndjsonStream 流式响应：每行一个 JSON 对象，写完立即 Flush
+// 第一条消息之前出错时返回普通的错误响应
+type ndjsonStream struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func newNDJSONStream(w http.ResponseWriter) *ndjsonStream {
+	return &ndjsonStream{w: w}
+}
+
+func (s *ndjsonStream) send(msg proto.Message) error {
+	data, err := marshalOptions.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if !s.started {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	http.NewResponseController(s.w).Flush()
+	return nil
+}
+
+func (s *ndjsonStream) finish(err error) {
+	switch {
+	case err == nil && !s.started:
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+		s.w.WriteHeader(http.StatusOK)
+	case err != nil && !s.started:
+		writeError(s.w, err)
+	case err != nil:
+		json.NewEncoder(s.w).Encode(newErrorBody(err))
+	}
+}
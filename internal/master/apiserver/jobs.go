@@ -0,0 +1,232 @@
+package apiserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/logstore"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// JobService 供用户调用：提交、查询、取消任务，读取任务输出
+// 用户不再直接读写 Store，HTTP 网关 (Gateway) 也通过它处理请求
+type JobService struct {
+	pb.UnimplementedJobServiceServer
+	store store.Store
+	logs  logstore.LogStore
+
+	// Master 的生命周期：GetLogs / WatchJob 可能一直不结束，Master 退出时由它终止
+	ctx context.Context
+
+	// 用户 Token 由 gRPC 拦截器 (见 Serve) 和 HTTP 网关校验；读取实时输出时以节点 Token 连接 Worker
+	auth auth.Config
+}
+
+func NewJobService(ctx context.Context, s store.Store, logs logstore.LogStore, cfg auth.Config) *JobService {
+	return &JobService{store: s, logs: logs, ctx: ctx, auth: cfg}
+}
+
+// SubmitJob 提交的任务从 Pending 开始，用户填写的状态被忽略
+// 写入前先补齐默认值 (包括生成 ID) 再校验，不合法时返回 INVALID_ARGUMENT (附带字段级错误)，
+// ID 已经被使用时返回 ALREADY_EXISTS；多个任务按工作流提交 (校验依赖、按拓扑序创建)
+func (j *JobService) SubmitJob(ctx context.Context, req *pb.SubmitJobRequest) (*pb.SubmitJobResponse, error) {
+	if len(req.Jobs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one job is required")
+	}
+	jobs := make([]*model.Job, 0, len(req.Jobs))
+	for _, pj := range req.Jobs {
+		job := convert.JobFromPB(pj)
+		job.Status = model.Job{}.Status
+		job.Status.State = model.JobPending
+		job.CreateTime = time.Time{}
+		job.ResourceVersion = 0
+		job.SetDefaults()
+		jobs = append(jobs, job)
+	}
+	if err := model.ValidateJobs(jobs); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var err error
+	if len(jobs) == 1 && len(jobs[0].Dependencies) == 0 {
+		err = j.store.CreateJob(ctx, jobs[0])
+	} else {
+		err = store.SubmitWorkflow(ctx, j.store, jobs)
+	}
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &pb.SubmitJobResponse{}
+	for _, job := range jobs {
+		log.Printf("[Master] Job %s submitted", job.ID)
+		resp.Jobs = append(resp.Jobs, convert.JobToPB(job))
+	}
+	return resp, nil
+}
+
+func (j *JobService) GetJob(ctx context.Context, req *pb.GetJobRequest) (*pb.Job, error) {
+	job, err := j.store.GetJob(ctx, req.JobId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return convert.JobToPB(job), nil
+}
+
+func (j *JobService) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	filter := &store.JobFilter{
+		NodeID:        req.NodeId,
+		NamePrefix:    req.NamePrefix,
+		Labels:        req.Labels,
+		CreatedAfter:  convert.TimeFromPB(req.CreatedAfterUnixNano),
+		CreatedBefore: convert.TimeFromPB(req.CreatedBeforeUnixNano),
+		Limit:         int(req.Limit),
+		Continue:      req.Continue,
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	for _, name := range req.States {
+		state, err := model.ParseJobState(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter.States = append(filter.States, state)
+	}
+
+	list, err := j.store.ListJobs(ctx, filter)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	resp := &pb.ListJobsResponse{Revision: list.Revision, Continue: list.Continue}
+	for _, job := range list.Jobs {
+		resp.Jobs = append(resp.Jobs, convert.JobToPB(job))
+	}
+	return resp, nil
+}
+
+func (j *JobService) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.CancelJobResponse, error) {
+	job, err := store.CancelJob(ctx, j.store, req.JobId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	// 已经结束的任务 CancelJob 原样返回
+	cancelled := job.Status.State == model.JobCancelled
+	if cancelled {
+		log.Printf("[Master] Job %s cancelled", job.ID)
+	}
+	return &pb.CancelJobResponse{Job: convert.JobToPB(job), Cancelled: cancelled}, nil
+}
+
+func (j *JobService) GetLogs(req *pb.GetLogsRequest, stream grpc.ServerStreamingServer[pb.GetLogsResponse]) error {
+	return j.streamLogs(stream.Context(), req, stream.Send)
+}
+
+func (j *JobService) WatchJob(req *pb.WatchJobRequest, stream grpc.ServerStreamingServer[pb.Job]) error {
+	return j.watchJob(stream.Context(), req.JobId, stream.Send)
+}
+
+// watchJob 推送任务的当前状态和之后的每次变化，直到任务结束
+func (j *JobService) watchJob(ctx context.Context, jobID string, send func(*pb.Job) error) error {
+	ctx, cancel := j.requestContext(ctx)
+	defer cancel()
+
+	job, revision, err := j.store.GetJobWithRevision(ctx, jobID)
+	if err != nil {
+		return toStatusError(err)
+	}
+	if err := send(convert.JobToPB(job)); err != nil {
+		return err
+	}
+
+	var backoff rewatchBackoff
+	jobCh := j.store.WatchJobs(ctx, revision+1)
+	for !job.Status.State.IsTerminal() {
+		select {
+		case event, ok := <-jobCh:
+			if !ok || event.Err != nil {
+				// Watch 中断：等一会儿再重新读取任务，从读取时的 Revision 继续 Watch
+				if err := backoff.wait(ctx); err != nil {
+					return toStatusError(err)
+				}
+				latest, revision, err := j.store.GetJobWithRevision(ctx, jobID)
+				if err != nil {
+					return toStatusError(err)
+				}
+				if latest.ResourceVersion != job.ResourceVersion {
+					if err := send(convert.JobToPB(latest)); err != nil {
+						return err
+					}
+				}
+				job = latest
+				jobCh = j.store.WatchJobs(ctx, revision+1)
+				continue
+			}
+			backoff.reset()
+			if event.Job.ID != jobID {
+				continue
+			}
+			if event.Type == store.JobDelete {
+				return status.Errorf(codes.NotFound, "job %s was deleted", jobID)
+			}
+			job = event.Job
+			if err := send(convert.JobToPB(job)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return toStatusError(ctx.Err())
+		}
+	}
+	return nil
+}
+
+// 重新 Watch 之前的等待时间，连续失败时逐次加倍 (Store 不可用时不能原地打转)
+const (
+	initialRewatchDelay = 100 * time.Millisecond
+	maxRewatchDelay     = 5 * time.Second
+)
+
+// rewatchBackoff 重新 Watch 的退避，收到正常事件后重置
+type rewatchBackoff struct {
+	delay time.Duration
+}
+
+// wait 等待下一次重新 Watch，ctx 结束时返回 ctx.Err()
+func (b *rewatchBackoff) wait(ctx context.Context) error {
+	if b.delay == 0 {
+		b.delay = initialRewatchDelay
+	} else {
+		b.delay = min(2*b.delay, maxRewatchDelay)
+	}
+	timer := time.NewTimer(b.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *rewatchBackoff) reset() {
+	b.delay = 0
+}
+
+// requestContext 请求结束或 Master 退出时取消
+func (j *JobService) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(j.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
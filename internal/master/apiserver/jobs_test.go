@@ -0,0 +1,95 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// countingStore 记录读取单个任务的次数
+type countingStore struct {
+	*store.MemoryStore
+	gets atomic.Int64
+}
+
+func (s *countingStore) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	s.gets.Add(1)
+	return s.MemoryStore.GetJob(ctx, id)
+}
+
+func (s *countingStore) GetJobWithRevision(ctx context.Context, id string) (*model.Job, int64, error) {
+	s.gets.Add(1)
+	return s.MemoryStore.GetJobWithRevision(ctx, id)
+}
+
+// TestWatchAfterCompaction 任务创建之后没有变化，它的 ResourceVersion 已经被压缩：
+// Watch 不能因此反复失败、原地打转，任务结束时仍然要收到最终状态
+func TestWatchAfterCompaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		watch func(ctx context.Context, j *JobService) error
+	}{
+		{
+			name: "WatchJob",
+			watch: func(ctx context.Context, j *JobService) error {
+				return j.watchJob(ctx, "job-1", func(*pb.Job) error { return nil })
+			},
+		},
+		{
+			name: "GetLogs follow",
+			watch: func(ctx context.Context, j *JobService) error {
+				req := &pb.GetLogsRequest{JobId: "job-1", Follow: true}
+				return j.streamLogs(ctx, req, func(*pb.GetLogsResponse) error { return nil })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			s := &countingStore{MemoryStore: store.NewMemoryStore()}
+
+			job := &model.Job{ID: "job-1"}
+			job.Status.State = model.JobPending
+			if err := s.CreateJob(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+			for i := range 1100 {
+				if err := s.CreateJob(ctx, &model.Job{ID: fmt.Sprintf("other-%d", i)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			j := NewJobService(ctx, s, s.MemoryStore, auth.Config{})
+			done := make(chan error, 1)
+			go func() { done <- tt.watch(ctx, j) }()
+
+			// 只应该在开始时读一次：Watch 从读取时的 Revision 开始，不会因为压缩而中断
+			time.Sleep(time.Second)
+			if n := s.gets.Load(); n != 1 {
+				t.Fatalf("job read %d times while nothing changed, want 1", n)
+			}
+
+			job, _ = s.GetJob(ctx, "job-1")
+			job.Status.State = model.JobSuccess
+			if err := s.UpdateJob(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("watch returned %v, want nil after the job finished", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("watch did not see the job finish")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package apiserver
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// liveLine 从 Worker 实时推送过来的一行输出，(attempt, seq, index) 是它在日志中的位置
+type liveLine struct {
+	attempt int
+	seq     int64
+	index   int
+	line    model.LogLine
+}
+
+// liveStream 一次 GetJobStream 调用：直接从正在执行任务的 Worker 读取实时输出
+// 日志后端按块写入，有一定延迟；实时流只是让输出更快出现，
+// 连不上 Worker 或流中断时，输出仍然会通过日志后端的 Watch 补上
+type liveStream struct {
+	nodeID  string
+	attempt int
+	lines   chan liveLine // 流结束 (任务结束 / 出错) 时关闭
+	cancel  context.CancelFunc
+}
+
+// openLiveStream 订阅任务在当前节点上这一次执行的输出
+// 节点不提供 WorkerService 或连不上时，返回的流直接结束
+func openLiveStream(ctx context.Context, s store.Store, cfg auth.Config, job *model.Job) *liveStream {
+	ctx, cancel := context.WithCancel(ctx)
+	ls := &liveStream{
+		nodeID:  job.Status.NodeID,
+		attempt: job.Status.Retries,
+		lines:   make(chan liveLine, 256),
+		cancel:  cancel,
+	}
+	go func() {
+		defer close(ls.lines)
+
+		addr, err := workerAddr(ctx, s, job.Status.NodeID)
+		if err != nil || addr == "" {
+			return
+		}
+		conn, err := cfg.Dial(addr, cfg.NodeToken)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		stream, err := pb.NewWorkerServiceClient(conn).GetJobStream(ctx, &pb.GetJobStreamRequest{JobId: job.ID})
+		if err != nil {
+			return
+		}
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return // 任务结束 (io.EOF)、Worker 不可达或任务不在这个节点上
+			}
+			l := liveLine{
+				attempt: int(resp.Attempt),
+				seq:     resp.Seq,
+				index:   int(resp.Index),
+				line: model.LogLine{
+					Time:   time.Unix(0, resp.TimeUnixNano),
+					Stream: resp.Stream,
+					Text:   string(resp.Output),
+				},
+			}
+			select {
+			case ls.lines <- l:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ls
+}
+
+// workerAddr 节点 WorkerService 的地址，节点不存在或不提供 WorkerService 时返回空
+func workerAddr(ctx context.Context, s store.Store, nodeID string) (string, error) {
+	nodes, err := s.ListNodes(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, node := range nodes {
+		if node.ID == nodeID && node.IP != "" && node.Port != 0 {
+			return net.JoinHostPort(node.IP, strconv.Itoa(node.Port)), nil
+		}
+	}
+	return "", nil
+}
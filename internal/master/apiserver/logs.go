@@ -0,0 +1,253 @@
+package apiserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// maxLogsResponseBytes 推送历史输出时单条消息的大小上限 (远小于 gRPC 默认 4MB 的消息上限)
+const maxLogsResponseBytes = 1 << 20
+
+// streamLogs 推送任务已有的输出；follow 时继续推送新的输出，直到任务结束
+// 新的输出有两个来源：日志后端的 Watch，以及正在执行任务的 Worker 的实时流 (更快)，
+// 两者按 (attempt, seq, 行号) 去重，每行只推送一次
+func (j *JobService) streamLogs(ctx context.Context, req *pb.GetLogsRequest, send func(*pb.GetLogsResponse) error) error {
+	ctx, cancel := j.requestContext(ctx)
+	defer cancel()
+
+	// 先 Watch 再读取已有的日志，两者之间写入的块靠去重，不会漏也不会重复
+	var chunkCh <-chan *model.LogChunk
+	if req.Follow {
+		chunkCh = j.logs.WatchJobLogs(ctx, req.JobId)
+	}
+	job, revision, err := j.store.GetJobWithRevision(ctx, req.JobId)
+	if err != nil {
+		return toStatusError(err)
+	}
+	chunks, err := j.logs.GetJobLogs(ctx, req.JobId)
+	if err != nil {
+		return toStatusError(err)
+	}
+
+	p := &logSender{send: send, printed: make(map[logChunkID]int), since: convert.TimeFromPB(req.SinceUnixNano)}
+	p.fill = func() error { return j.catchUp(ctx, req.JobId, p) }
+	if err := p.history(chunks, int(req.Tail)); err != nil || !req.Follow {
+		return err
+	}
+
+	// 任务运行时另外直接订阅 Worker 的实时输出；节点或执行次数变化 (重试) 时重新订阅
+	var live *liveStream
+	var liveCh <-chan liveLine
+	followLive := func() {
+		if job.Status.State != model.JobRunning ||
+			(live != nil && live.nodeID == job.Status.NodeID && live.attempt == job.Status.Retries) {
+			return
+		}
+		if live != nil {
+			live.cancel()
+		}
+		live = openLiveStream(ctx, j.store, j.auth, job)
+		liveCh = live.lines
+	}
+	defer func() {
+		if live != nil {
+			live.cancel()
+		}
+	}()
+
+	var jobBackoff, chunkBackoff rewatchBackoff
+	jobCh := j.store.WatchJobs(ctx, revision+1)
+	for !job.Status.State.IsTerminal() {
+		followLive()
+		select {
+		case l, ok := <-liveCh:
+			if !ok {
+				// 实时流结束：剩下的输出由日志后端的 Watch 补上
+				liveCh = nil
+				continue
+			}
+			if err := p.live(l); err != nil {
+				return err
+			}
+
+		case chunk, ok := <-chunkCh:
+			if !ok {
+				// 日志 Watch 中断：等一会儿再重新 Watch，再补上中断期间写入的块
+				if err := chunkBackoff.wait(ctx); err != nil {
+					return toStatusError(err)
+				}
+				chunkCh = j.logs.WatchJobLogs(ctx, req.JobId)
+				if err := j.catchUp(ctx, req.JobId, p); err != nil {
+					return err
+				}
+				continue
+			}
+			chunkBackoff.reset()
+			if err := p.chunk(chunk); err != nil {
+				return err
+			}
+
+		case event, ok := <-jobCh:
+			if !ok || event.Err != nil {
+				// 任务 Watch 中断：等一会儿再重新读取任务，从读取时的 Revision 继续 Watch
+				if err := jobBackoff.wait(ctx); err != nil {
+					return toStatusError(err)
+				}
+				if job, revision, err = j.store.GetJobWithRevision(ctx, req.JobId); err != nil {
+					return toStatusError(err)
+				}
+				jobCh = j.store.WatchJobs(ctx, revision+1)
+				continue
+			}
+			jobBackoff.reset()
+			if event.Job.ID != req.JobId {
+				continue
+			}
+			if event.Type == store.JobDelete {
+				return toStatusError(store.ErrNotFound)
+			}
+			job = event.Job
+
+		case <-ctx.Done():
+			return toStatusError(ctx.Err())
+		}
+	}
+
+	// Worker 先写完日志再写最终状态，这里最后补读一次，保证输出完整
+	return j.catchUp(ctx, req.JobId, p)
+}
+
+// catchUp 重新读取全部日志，补发还没推送过的行
+// 读取失败 (比如 fs 后端的节点已经下线) 只打印警告，能推送的已经推送了
+func (j *JobService) catchUp(ctx context.Context, jobID string, p *logSender) error {
+	chunks, err := j.logs.GetJobLogs(ctx, jobID)
+	if err != nil {
+		log.Printf("[Master] ⚠️ Failed to get logs of job %s: %v", jobID, err)
+		return nil
+	}
+	for _, chunk := range chunks {
+		if err := p.chunk(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logChunkID 日志块在一个任务内的唯一标识
+type logChunkID struct {
+	attempt int
+	seq     int64
+}
+
+// logSender 按行推送任务输出
+// 记录每个块已经推送了多少行，历史、Watch 和实时流收到的同一行只推送一次，并且按顺序推送
+type logSender struct {
+	send    func(*pb.GetLogsResponse) error
+	printed map[logChunkID]int
+	since   time.Time // 早于这个时间的行不推送
+
+	// fill 从日志后端补齐还没推送的块 (实时流跑在日志后端的 Watch 前面时调用)，为 nil 时不补齐
+	fill func() error
+}
+
+// history 推送已有的输出，tail > 0 时只推送最后 tail 行
+func (p *logSender) history(chunks []*model.LogChunk, tail int) error {
+	type attemptLine struct {
+		attempt int
+		line    model.LogLine
+	}
+	var lines []attemptLine
+	for _, chunk := range chunks {
+		p.printed[logChunkID{chunk.Attempt, chunk.Seq}] = len(chunk.Lines)
+		for _, line := range chunk.Lines {
+			if !line.Time.Before(p.since) {
+				lines = append(lines, attemptLine{chunk.Attempt, line})
+			}
+		}
+	}
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+
+	// 同一次执行的连续多行合并成一条消息，每条不超过 maxLogsResponseBytes
+	var resp *pb.GetLogsResponse
+	size := 0
+	for _, l := range lines {
+		lineSize := len(l.line.Text) + len(l.line.Stream) + 16 // 粗略估算编码后的大小
+		if resp != nil && (int(resp.Attempt) != l.attempt || size+lineSize > maxLogsResponseBytes) {
+			if err := p.send(resp); err != nil {
+				return err
+			}
+			resp = nil
+		}
+		if resp == nil {
+			resp = &pb.GetLogsResponse{Attempt: int32(l.attempt)}
+			size = 0
+		}
+		resp.Lines = append(resp.Lines, convert.LogLineToPB(l.line))
+		size += lineSize
+	}
+	if resp != nil {
+		return p.send(resp)
+	}
+	return nil
+}
+
+// chunk 推送一个日志块中还没推送过的行
+func (p *logSender) chunk(chunk *model.LogChunk) error {
+	id := logChunkID{chunk.Attempt, chunk.Seq}
+	start := p.printed[id]
+	if start >= len(chunk.Lines) {
+		return nil
+	}
+	p.printed[id] = len(chunk.Lines)
+	return p.sendLines(chunk.Attempt, chunk.Lines[start:])
+}
+
+// live 推送实时流中的一行 (已经推送过的忽略)
+// 前面还有没推送的行时 (实时流比日志后端的 Watch 快)，先从日志后端补齐，保证输出不缺行、不乱序；
+// 补齐之后仍然缺的行已经丢失了 (Worker 写入日志后端失败)，不再等待
+func (p *logSender) live(l liveLine) error {
+	id := logChunkID{l.attempt, l.seq}
+	if l.index < p.printed[id] {
+		return nil
+	}
+	if p.missingBefore(l) && p.fill != nil {
+		if err := p.fill(); err != nil {
+			return err
+		}
+		if l.index < p.printed[id] {
+			return nil
+		}
+	}
+	p.printed[id] = l.index + 1
+	return p.sendLines(l.attempt, []model.LogLine{l.line})
+}
+
+// missingBefore l 之前是否还有没推送的行：同一块中前面的行，或者 (块的第一行) 上一块
+// 实时流按顺序推送，上一块推送过任何一行就说明它已经完整了
+func (p *logSender) missingBefore(l liveLine) bool {
+	if l.index > p.printed[logChunkID{l.attempt, l.seq}] {
+		return true
+	}
+	return l.index == 0 && l.seq > 0 && p.printed[logChunkID{l.attempt, l.seq - 1}] == 0
+}
+
+func (p *logSender) sendLines(attempt int, lines []model.LogLine) error {
+	resp := &pb.GetLogsResponse{Attempt: int32(attempt)}
+	for _, line := range lines {
+		if !line.Time.Before(p.since) {
+			resp.Lines = append(resp.Lines, convert.LogLineToPB(line))
+		}
+	}
+	if len(resp.Lines) == 0 {
+		return nil
+	}
+	return p.send(resp)
+}
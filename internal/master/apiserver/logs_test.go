@@ -0,0 +1,210 @@
+package apiserver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+// step 依次喂给 logSender 的输入：日志后端的块或者实时流的一行
+type step struct {
+	chunk *model.LogChunk
+	live  *liveLine
+}
+
+func logChunk(attempt int, seq int64, texts ...string) *model.LogChunk {
+	chunk := &model.LogChunk{Attempt: attempt, Seq: seq}
+	for _, text := range texts {
+		chunk.Lines = append(chunk.Lines, model.LogLine{Text: text})
+	}
+	return chunk
+}
+
+func liveAt(attempt int, seq int64, index int, text string) *liveLine {
+	return &liveLine{attempt: attempt, seq: seq, index: index, line: model.LogLine{Text: text}}
+}
+
+func TestLogSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []*model.LogChunk
+		stored  []*model.LogChunk // fill 时日志后端里已有的块
+		steps   []step
+		want    []string
+		fills   int
+	}{
+		{
+			name:    "live then chunk",
+			history: []*model.LogChunk{logChunk(0, 0, "a", "b")},
+			steps: []step{
+				{live: liveAt(0, 1, 0, "c")},
+				{live: liveAt(0, 1, 1, "d")},
+				{chunk: logChunk(0, 1, "c", "d", "e")},
+				{live: liveAt(0, 1, 2, "e")},
+			},
+			want: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:    "history and live overlap",
+			history: []*model.LogChunk{logChunk(0, 0, "a", "b")},
+			steps: []step{
+				{live: liveAt(0, 0, 0, "a")},
+				{live: liveAt(0, 0, 1, "b")},
+				{chunk: logChunk(0, 0, "a", "b")},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name:    "gap inside a chunk is filled from the store",
+			history: []*model.LogChunk{logChunk(0, 0, "a")},
+			stored:  []*model.LogChunk{logChunk(0, 0, "a"), logChunk(0, 1, "b", "c")},
+			steps: []step{
+				{live: liveAt(0, 1, 2, "d")},
+				{chunk: logChunk(0, 1, "b", "c", "d")},
+			},
+			want:  []string{"a", "b", "c", "d"},
+			fills: 1,
+		},
+		{
+			name:   "live ahead of a whole chunk",
+			stored: []*model.LogChunk{logChunk(0, 0, "a", "b")},
+			steps: []step{
+				{live: liveAt(0, 1, 0, "c")},
+				{live: liveAt(0, 1, 1, "d")},
+				{chunk: logChunk(0, 0, "a", "b")},
+			},
+			want:  []string{"a", "b", "c", "d"},
+			fills: 1,
+		},
+		{
+			name: "lost chunk is skipped after one fill",
+			steps: []step{
+				{live: liveAt(1, 3, 0, "x")},
+				{live: liveAt(1, 3, 1, "y")},
+				{live: liveAt(1, 4, 0, "z")},
+			},
+			want:  []string{"x", "y", "z"},
+			fills: 1,
+		},
+		{
+			name: "retry starts a new attempt",
+			steps: []step{
+				{live: liveAt(0, 0, 0, "a")},
+				{live: liveAt(1, 0, 0, "b")},
+				{chunk: logChunk(1, 0, "b")},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			p := &logSender{
+				printed: make(map[logChunkID]int),
+				send: func(resp *pb.GetLogsResponse) error {
+					for _, line := range resp.Lines {
+						got = append(got, line.Text)
+					}
+					return nil
+				},
+			}
+			fills := 0
+			p.fill = func() error {
+				fills++
+				for _, chunk := range tt.stored {
+					if err := p.chunk(chunk); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			if err := p.history(tt.history, 0); err != nil {
+				t.Fatal(err)
+			}
+			for i, s := range tt.steps {
+				var err error
+				if s.chunk != nil {
+					err = p.chunk(s.chunk)
+				} else {
+					err = p.live(*s.live)
+				}
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+			if fills != tt.fills {
+				t.Errorf("filled %d times, want %d", fills, tt.fills)
+			}
+		})
+	}
+}
+
+func TestLogSenderHistoryTail(t *testing.T) {
+	var got []string
+	p := &logSender{
+		printed: make(map[logChunkID]int),
+		send: func(resp *pb.GetLogsResponse) error {
+			for _, line := range resp.Lines {
+				got = append(got, fmt.Sprintf("%d:%s", resp.Attempt, line.Text))
+			}
+			return nil
+		},
+	}
+	chunks := []*model.LogChunk{logChunk(0, 0, "a", "b"), logChunk(1, 0, "c"), logChunk(1, 1, "d")}
+	if err := p.history(chunks, 3); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"0:b", "1:c", "1:d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("sent %v, want %v", got, want)
+	}
+	// tail 之外的行也算已经推送过，之后的 Watch 不会再补发
+	if err := p.chunk(chunks[0]); err != nil || len(got) != 3 {
+		t.Fatalf("chunk() re-sent history: %v, %v", got, err)
+	}
+}
+
+func TestLogSenderHistorySplitsLargeOutput(t *testing.T) {
+	var sizes []int
+	lines := 0
+	p := &logSender{
+		printed: make(map[logChunkID]int),
+		send: func(resp *pb.GetLogsResponse) error {
+			sizes = append(sizes, proto.Size(resp))
+			lines += len(resp.Lines)
+			return nil
+		},
+	}
+	text := strings.Repeat("x", 1000)
+	var chunks []*model.LogChunk
+	for seq := range 50 {
+		chunk := &model.LogChunk{Seq: int64(seq)}
+		for range 100 {
+			chunk.Lines = append(chunk.Lines, model.LogLine{Stream: model.StreamStdout, Text: text})
+		}
+		chunks = append(chunks, chunk)
+	}
+	if err := p.history(chunks, 0); err != nil {
+		t.Fatal(err)
+	}
+	if lines != 5000 {
+		t.Fatalf("sent %d lines, want 5000", lines)
+	}
+	for i, size := range sizes {
+		if size > maxLogsResponseBytes+maxLogsResponseBytes/10 {
+			t.Errorf("message %d is %d bytes, want at most about %d", i, size, maxLogsResponseBytes)
+		}
+	}
+	if len(sizes) < 4 {
+		t.Errorf("sent %d messages for ~5MB of output, want it split", len(sizes))
+	}
+}
@@ -0,0 +1,151 @@
+package apiserver
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// MasterService 供 Worker 调用：节点注册、心跳、任务状态上报
+// Master 是集群状态的唯一写入方，Worker 不再直接写 Store 中的 Job / Node
+type MasterService struct {
+	pb.UnimplementedMasterServiceServer
+	store store.Store
+
+	// 已注册的节点 (心跳时据此刷新 Store 中的节点记录)
+	// Master 重启后为空，Worker 的心跳会收到 NOT_FOUND 并重新注册
+	mu    sync.Mutex
+	nodes map[string]model.Node
+}
+
+func NewMasterService(s store.Store) *MasterService {
+	return &MasterService{
+		store: s,
+		nodes: make(map[string]model.Node),
+	}
+}
+
+func (m *MasterService) RegisterNode(ctx context.Context, req *pb.RegisterNodeRequest) (*pb.RegisterNodeResponse, error) {
+	if req.NodeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "node_id is required")
+	}
+	node := model.Node{
+		ID:            req.NodeId,
+		IP:            req.Ip,
+		Version:       req.Version,
+		Port:          int(req.Port),
+		LogAddr:       req.LogAddr,
+		JobTypes:      make([]model.JobType, 0, len(req.JobTypes)),
+		TotalCap:      convert.ResourceFromPB(req.TotalResource),
+		Status:        model.NodeReady,
+		LastHeartbeat: time.Now().Unix(),
+	}
+	for _, t := range req.JobTypes {
+		node.JobTypes = append(node.JobTypes, model.JobType(t))
+	}
+	if err := m.store.RegisterNode(ctx, &node); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	m.mu.Lock()
+	m.nodes[node.ID] = node
+	m.mu.Unlock()
+	log.Printf("[Master] Node %s registered (ip: %s, cpu: %dm, memory: %d, job types: %v)",
+		node.ID, node.IP, node.TotalCap.MilliCPU, node.TotalCap.Memory, node.JobTypes)
+	return &pb.RegisterNodeResponse{Success: true}, nil
+}
+
+// SendHeartbeat 刷新节点的心跳时间和资源占用 (同时续约节点的租约)
+// 心跳时间以 Master 的时钟为准，避免节点之间的时钟偏差导致误判为失联
+func (m *MasterService) SendHeartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	m.mu.Lock()
+	node, ok := m.nodes[req.NodeId]
+	m.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "node %s is not registered", req.NodeId)
+	}
+
+	available := convert.ResourceFromPB(req.AvailableResource)
+	node.Allocated = model.Resource{
+		MilliCPU: max(node.TotalCap.MilliCPU-available.MilliCPU, 0),
+		Memory:   max(node.TotalCap.Memory-available.Memory, 0),
+	}
+	node.Status = model.NodeReady
+	node.LastHeartbeat = time.Now().Unix()
+	if err := m.store.RegisterNode(ctx, &node); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	m.mu.Lock()
+	m.nodes[node.ID] = node
+	m.mu.Unlock()
+	return &pb.HeartbeatResponse{}, nil
+}
+
+// UpdateJobStatus Worker 上报任务开始运行 (Running) 或结束 (Success / Failed)
+// 只接受任务当前绑定的节点、当前这次执行的上报；过期的上报 (任务已被取消、改派或已经重试) 返回 FAILED_PRECONDITION
+func (m *MasterService) UpdateJobStatus(ctx context.Context, req *pb.UpdateJobStatusRequest) (*pb.UpdateJobStatusResponse, error) {
+	state, err := model.ParseJobState(req.State)
+	if err != nil || (state != model.JobRunning && state != model.JobSuccess && state != model.JobFailed) {
+		return nil, status.Errorf(codes.InvalidArgument, "state must be Running, Success or Failed, got %q", req.State)
+	}
+	if req.JobId == "" || req.NodeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id and node_id are required")
+	}
+
+	for {
+		job, err := m.store.GetJob(ctx, req.JobId)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		if job.Status.NodeID != req.NodeId || job.Status.Retries != int(req.Attempt) {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"job %s attempt %d is no longer assigned to node %s (state: %s, node: %s, attempt: %d)",
+				job.ID, req.Attempt, req.NodeId, job.Status.State, job.Status.NodeID, job.Status.Retries)
+		}
+
+		resp := &pb.UpdateJobStatusResponse{}
+		now := time.Now()
+		switch {
+		case state == model.JobRunning && job.Status.State == model.JobRunning:
+			// Worker 重试了上一次的上报 (响应丢失)
+			return resp, nil
+		case state == model.JobRunning && job.Status.State == model.JobScheduled:
+			job.Status.State = model.JobRunning
+		case state != model.JobRunning && job.Status.State == model.JobRunning:
+			job.Status.State = state
+			job.Status.ExitCode = int(req.ExitCode)
+			job.Status.Error = req.ErrorMessage
+			job.Status.Reason = req.Reason
+			job.Status.PeakMemory = req.PeakMemoryBytes
+			job.Status.CPUTimeMs = req.CpuTimeMs
+			job.Status.EndTime = now
+			// 失败且还有重试次数的任务退回 Pending，由调度器在退避结束后重新调度
+			if job.FinishAttempt(now) {
+				resp.Retrying = true
+				resp.RetryAfterMs = job.Status.NextRetryTime.Sub(now).Milliseconds()
+			}
+		default:
+			return nil, status.Errorf(codes.FailedPrecondition, "job %s cannot change from %s to %s",
+				job.ID, job.Status.State, state)
+		}
+
+		err = m.store.CompareAndSwapJob(ctx, job)
+		if store.IsConflict(err) {
+			continue // 期间被别人修改过 (比如用户取消)，重新读取后再判断
+		}
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		return resp, nil
+	}
+}
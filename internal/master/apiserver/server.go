@@ -0,0 +1,86 @@
+// Package apiserver Master 对外提供的 gRPC 接口
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// Master 对外接口的默认监听地址
+const (
+	DefaultAddr     = ":9090" // gRPC
+	DefaultHTTPAddr = ":8080" // HTTP 网关 (JSON)
+)
+
+// Serve 在 lis 上提供 Master 的 gRPC 接口 (MasterService + JobService)，直到 ctx 结束 (等待进行中的请求处理完再返回)
+// MasterService 要求节点 Token，JobService 要求用户 Token；配置了证书时使用 TLS
+func Serve(ctx context.Context, lis net.Listener, s store.Store, jobs *JobService, cfg auth.Config) error {
+	opts, err := cfg.ServerOptions(auth.Tokens{
+		pb.MasterService_ServiceDesc.ServiceName: cfg.NodeToken,
+		pb.JobService_ServiceDesc.ServiceName:    cfg.APIToken,
+	})
+	if err != nil {
+		return err
+	}
+	srv := grpc.NewServer(opts...)
+	pb.RegisterMasterServiceServer(srv, NewMasterService(s))
+	pb.RegisterJobServiceServer(srv, jobs)
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	log.Printf("[Master] gRPC API listening on %s", lis.Addr())
+	return srv.Serve(lis)
+}
+
+// toStatusError 把 Store 的错误转换为 gRPC 状态码
+// 其他错误 (Etcd 暂时不可用等) 返回 UNAVAILABLE，调用方可以稍后重试
+func toStatusError(err error) error {
+	var invalid *model.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return validationStatus(invalid)
+	case errors.Is(err, store.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, store.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, store.ErrInvalidContinue):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case store.IsConflict(err):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// validationStatus INVALID_ARGUMENT，每个不合法的字段放进 BadRequest 详情
+func validationStatus(invalid *model.ValidationError) error {
+	st := status.New(codes.InvalidArgument, invalid.Error())
+	br := &errdetails.BadRequest{}
+	for _, f := range invalid.Fields {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Reason,
+		})
+	}
+	if detailed, err := st.WithDetails(br); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
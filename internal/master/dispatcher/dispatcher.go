@@ -0,0 +1,139 @@
+// Package dispatcher 通过 WorkerService 把调度结果推送给 Worker
+package dispatcher
+
+import (
+	"context"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/internal/master/scheduler"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// rpcTimeout 单次推送的超时时间
+const rpcTimeout = 5 * time.Second
+
+// Dispatcher 异步调用 Worker 的 StartJob / StopJob
+// 失败只打印日志：Worker 会通过 Watch 领取任务、停止被取消的任务
+type Dispatcher struct {
+	store store.Store
+	auth  auth.Config // 以节点 Token 调用 WorkerService
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn // ip:port -> 连接 (gRPC 连接断开后会自己重连)
+}
+
+var _ scheduler.Dispatcher = (*Dispatcher)(nil)
+
+func New(s store.Store, cfg auth.Config) *Dispatcher {
+	return &Dispatcher{
+		store: s,
+		auth:  cfg,
+		conns: make(map[string]*grpc.ClientConn),
+	}
+}
+
+// StartJob 通知节点开始执行刚绑定给它的任务
+func (d *Dispatcher) StartJob(node *model.Node, job *model.Job) {
+	client, ok := d.client(node)
+	if !ok {
+		return
+	}
+	req := &pb.StartJobRequest{JobId: job.ID}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		resp, err := client.StartJob(ctx, req)
+		if status.Code(err) == codes.FailedPrecondition {
+			return // Worker 通过 Watch 先一步开始执行了 (或者任务已经被取消)
+		}
+		if err != nil {
+			log.Printf("[Dispatcher] ⚠️ Failed to push job %s to node %s (worker will pick it up via watch): %v",
+				req.JobId, node.ID, err)
+			return
+		}
+		if !resp.Success {
+			log.Printf("[Dispatcher] Job %s is already running on node %s", req.JobId, node.ID)
+		}
+	}()
+}
+
+// StopJob 通知节点停止执行被取消 / 删除的任务
+func (d *Dispatcher) StopJob(nodeID, jobID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+
+		node, err := d.findNode(ctx, nodeID)
+		if err != nil {
+			log.Printf("[Dispatcher] ⚠️ Failed to look up node %s to stop job %s: %v", nodeID, jobID, err)
+			return
+		}
+		if node == nil {
+			return // 节点已经不在了，任务也不会继续执行
+		}
+		client, ok := d.client(node)
+		if !ok {
+			return
+		}
+		if _, err := client.StopJob(ctx, &pb.StopJobRequest{JobId: jobID}); err != nil {
+			log.Printf("[Dispatcher] ⚠️ Failed to stop job %s on node %s (worker will stop it via watch): %v",
+				jobID, nodeID, err)
+		}
+	}()
+}
+
+func (d *Dispatcher) findNode(ctx context.Context, nodeID string) (*model.Node, error) {
+	nodes, err := d.store.ListNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if node.ID == nodeID {
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+// client 节点 WorkerService 的客户端；节点没有提供 WorkerService 时返回 false
+func (d *Dispatcher) client(node *model.Node) (pb.WorkerServiceClient, bool) {
+	if node.IP == "" || node.Port == 0 {
+		return nil, false
+	}
+	addr := net.JoinHostPort(node.IP, strconv.Itoa(node.Port))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	conn, ok := d.conns[addr]
+	if !ok {
+		var err error
+		conn, err = d.auth.Dial(addr, d.auth.NodeToken)
+		if err != nil {
+			log.Printf("[Dispatcher] ⚠️ Failed to connect to node %s at %s: %v", node.ID, addr, err)
+			return nil, false
+		}
+		d.conns[addr] = conn
+	}
+	return pb.NewWorkerServiceClient(conn), true
+}
+
+// Close 关闭所有连接
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for addr, conn := range d.conns {
+		conn.Close()
+		delete(d.conns, addr)
+	}
+}
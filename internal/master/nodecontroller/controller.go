@@ -0,0 +1,114 @@
+package nodecontroller
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// checkInterval 巡检节点心跳的周期
+const checkInterval = 2 * time.Second
+
+// NodeController 节点生命周期控制器 (运行在 Master)
+// 1. 定期巡检所有节点，心跳超过 model.NodeHeartbeatTimeout 的节点会被标记为 OFFLINE
+// 2. 绑定在 OFFLINE / 已消失节点上的未完成任务会被重新调度 (故障自愈)
+type NodeController struct {
+	store store.Store
+}
+
+// NewNodeController 构造函数
+func NewNodeController(s store.Store) *NodeController {
+	return &NodeController{
+		store: s,
+	}
+}
+
+// Run 启动巡检主循环 (后台常驻 Goroutine)
+func (c *NodeController) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	log.Println("[NodeController] Started, monitoring node heartbeats...")
+
+	for {
+		select {
+		case <-ticker.C:
+			c.checkNodes(ctx)
+		case <-ctx.Done():
+			log.Println("[NodeController] Stopped.")
+			return
+		}
+	}
+}
+
+// checkNodes 执行一次巡检：先标记失联节点，再救回上面的任务
+func (c *NodeController) checkNodes(ctx context.Context) {
+	// 注意顺序：必须先 List Job 再 List Node
+	// Job 被 Bind 之前它的节点一定已经注册，这样新节点上的任务不会被误判为"节点已消失"
+	jobList, err := c.store.ListJobs(ctx, &store.JobFilter{
+		States: []model.JobState{model.JobScheduled, model.JobRunning},
+	})
+	if err != nil {
+		log.Printf("[Error] Failed to list jobs: %v", err)
+		return
+	}
+
+	nodes, err := c.store.ListNodes(ctx)
+	if err != nil {
+		log.Printf("[Error] Failed to list nodes: %v", err)
+		return
+	}
+
+	now := time.Now()
+	alive := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if node.Status != model.NodeOffline && node.IsStale(now) {
+			log.Printf("[NodeController] ⚠️ Node %s heartbeat timeout (last: %s), marking OFFLINE",
+				node.ID, time.Unix(node.LastHeartbeat, 0).Format(time.RFC3339))
+			node.Status = model.NodeOffline
+			if err := c.store.UpdateNode(ctx, node); err != nil {
+				log.Printf("[Error] Failed to mark node %s offline: %v", node.ID, err)
+			}
+		}
+		alive[node.ID] = node.Status != model.NodeOffline
+	}
+
+	for _, job := range jobList.Jobs {
+		if job.Status.State != model.JobScheduled && job.Status.State != model.JobRunning {
+			continue
+		}
+		if alive[job.Status.NodeID] {
+			continue
+		}
+		c.rescueJob(ctx, job)
+	}
+}
+
+// rescueJob 处理滞留在失联节点上的任务
+// 按一次失败的执行处理：还有重试次数 -> 退避后退回 Pending 重新调度；否则直接标记 Failed
+func (c *NodeController) rescueJob(ctx context.Context, job *model.Job) {
+	lostNode := job.Status.NodeID
+	lostState := job.Status.State
+	now := time.Now()
+
+	job.Status.State = model.JobFailed
+	job.Status.Error = fmt.Sprintf("node %s went offline while job was %s", lostNode, lostState)
+	job.Status.Reason = model.ReasonNodeLost
+	job.Status.EndTime = now
+	if job.FinishAttempt(now) {
+		log.Printf("[NodeController] ♻️ Job %s lost on node %s, rescheduling after backoff (retry %d/%d)",
+			job.ID, lostNode, job.Status.Retries, job.Spec.RetryCount)
+	} else {
+		log.Printf("[NodeController] ❌ Job %s lost on node %s, no retries left (retry_count=%d), marking Failed",
+			job.ID, lostNode, job.Spec.RetryCount)
+	}
+
+	// CAS 写入：如果任务恰好在这期间被 Worker 更新过，就放弃，下一轮巡检再处理
+	if err := c.store.CompareAndSwapJob(ctx, job); err != nil {
+		log.Printf("[Error] Failed to update job %s: %v", job.ID, err)
+	}
+}
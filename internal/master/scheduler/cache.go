@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"sync"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// allocation 记录一个已绑定任务占用的资源
+type allocation struct {
+	nodeID string
+	res    model.Resource
+
+	// assumed 为 true 表示调度器已经预占、但 Bind 结果还没有从 Store 中观察到
+	// baseVersion 是 Bind 时读到的任务版本，版本号不超过它的事件都是"旧消息"，不能用来释放预占
+	// prev 是预占之前的条目 (通常没有)，Bind 失败时恢复
+	assumed     bool
+	baseVersion int64
+	prev        *allocation
+}
+
+// allocationCache 调度器视角下的权威资源账本
+// Node.Allocated 由这里根据"已绑定且未结束"的任务推导，而不是信任 Worker 上报的数据：
+//   - Bind 前先 assume (预占)，Bind 失败再 forget (只撤销这一次预占)
+//   - Watch 到任务进入终态 (Success/Failed/Cancelled) 或被删除时释放
+type allocationCache struct {
+	mu   sync.RWMutex
+	jobs map[string]allocation // jobID -> allocation
+}
+
+func newAllocationCache() *allocationCache {
+	return &allocationCache{
+		jobs: make(map[string]allocation),
+	}
+}
+
+// assume 在写入 Bind 结果之前预占资源，防止并发调度超卖
+func (c *allocationCache) assume(job *model.Job, nodeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a := allocation{
+		nodeID:      nodeID,
+		res:         job.ResReq,
+		assumed:     true,
+		baseVersion: job.ResourceVersion,
+	}
+	if prev, ok := c.jobs[job.ID]; ok {
+		a.prev = &prev
+	}
+	c.jobs[job.ID] = a
+}
+
+// forget 撤销 assume(job, nodeID) 时 (任务版本为 baseVersion) 做的预占，恢复预占之前的条目
+// 条目已经被更新的 Watch 事件或者另一次预占替换时什么也不做：
+// 比如 Bind 冲突是因为别的调度协程 / Master 抢先绑定了，账本里记的是赢家的占用，不能释放
+func (c *allocationCache) forget(jobID, nodeID string, baseVersion int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a, ok := c.jobs[jobID]
+	if !ok || !a.assumed || a.nodeID != nodeID || a.baseVersion != baseVersion {
+		return
+	}
+	if a.prev != nil {
+		c.jobs[jobID] = *a.prev
+	} else {
+		delete(c.jobs, jobID)
+	}
+}
+
+// observe 根据任务的最新状态更新账本
+func (c *allocationCache) observe(eventType store.JobEventType, job *model.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observeLocked(eventType, job)
+}
+
+// replace 重新 List 之后用全量数据重建账本
+// 不在列表里的已确认条目说明任务已被删除；尚未确认的预占保留，等 Bind 结果自己回来
+func (c *allocationCache) replace(jobs []*model.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		seen[job.ID] = true
+		c.observeLocked(store.JobUpdate, job)
+	}
+	for id, a := range c.jobs {
+		if !seen[id] && !a.assumed {
+			delete(c.jobs, id)
+		}
+	}
+}
+
+func (c *allocationCache) observeLocked(eventType store.JobEventType, job *model.Job) {
+	if a, ok := c.jobs[job.ID]; ok && a.assumed && job.ResourceVersion <= a.baseVersion {
+		return // Bind 之前的旧事件
+	}
+
+	if eventType == store.JobDelete || !holdsResources(job) {
+		delete(c.jobs, job.ID)
+		return
+	}
+	c.jobs[job.ID] = allocation{nodeID: job.Status.NodeID, res: job.ResReq}
+}
+
+// nodeOf 任务当前占用资源所在的节点 (包括尚未确认的预占)
+func (c *allocationCache) nodeOf(jobID string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	a, ok := c.jobs[jobID]
+	return a.nodeID, ok
+}
+
+// allocated 汇总某个节点上所有任务的资源占用
+func (c *allocationCache) allocated(nodeID string) model.Resource {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var total model.Resource
+	for _, a := range c.jobs {
+		if a.nodeID == nodeID {
+			total.MilliCPU += a.res.MilliCPU
+			total.Memory += a.res.Memory
+		}
+	}
+	return total
+}
+
+// holdsResources 已分配节点且尚未结束的任务才占用资源
+func holdsResources(job *model.Job) bool {
+	if job.Status.NodeID == "" {
+		return false
+	}
+	return job.Status.State == model.JobScheduled || job.Status.State == model.JobRunning
+}
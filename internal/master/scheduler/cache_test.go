@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"testing"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+func cacheJob(id string, version int64, state model.JobState, nodeID string, cpu int64) *model.Job {
+	job := &model.Job{ID: id, ResourceVersion: version, ResReq: model.Resource{MilliCPU: cpu, Memory: cpu}}
+	job.Status.State = state
+	job.Status.NodeID = nodeID
+	return job
+}
+
+func TestAllocationCache(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(c *allocationCache)
+		want map[string]int64 // nodeID -> 已分配的 MilliCPU
+	}{
+		{
+			name: "assume reserves resources",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.assume(cacheJob("b", 6, model.JobPending, "", 200), "n1")
+			},
+			want: map[string]int64{"n1": 300},
+		},
+		{
+			name: "forget undoes a failed bind",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.forget("a", "n1", 5)
+			},
+			want: map[string]int64{"n1": 0},
+		},
+		{
+			name: "stale events do not release an assumption",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.observe(store.JobUpdate, cacheJob("a", 5, model.JobPending, "", 100))
+			},
+			want: map[string]int64{"n1": 100},
+		},
+		{
+			name: "observed bind confirms the assumption",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.observe(store.JobUpdate, cacheJob("a", 6, model.JobScheduled, "n1", 100))
+				c.forget("a", "n1", 5) // 确认之后不能再撤销
+			},
+			want: map[string]int64{"n1": 100},
+		},
+		{
+			name: "conflict with a winner on another node keeps the winner",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.observe(store.JobUpdate, cacheJob("a", 6, model.JobScheduled, "n2", 100))
+				c.forget("a", "n1", 5)
+			},
+			want: map[string]int64{"n1": 0, "n2": 100},
+		},
+		{
+			name: "forget restores the previous assumption",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n2")
+				c.forget("a", "n2", 5)
+			},
+			want: map[string]int64{"n1": 100, "n2": 0},
+		},
+		{
+			name: "forget of an older attempt is ignored",
+			run: func(c *allocationCache) {
+				c.assume(cacheJob("a", 5, model.JobPending, "", 100), "n1")
+				c.assume(cacheJob("a", 7, model.JobPending, "", 100), "n2")
+				c.forget("a", "n1", 5)
+			},
+			want: map[string]int64{"n1": 0, "n2": 100},
+		},
+		{
+			name: "finished jobs release resources",
+			run: func(c *allocationCache) {
+				c.observe(store.JobUpdate, cacheJob("a", 6, model.JobRunning, "n1", 100))
+				c.observe(store.JobUpdate, cacheJob("b", 7, model.JobRunning, "n1", 200))
+				c.observe(store.JobUpdate, cacheJob("a", 8, model.JobSuccess, "n1", 100))
+				c.observe(store.JobDelete, cacheJob("b", 9, model.JobRunning, "n1", 200))
+			},
+			want: map[string]int64{"n1": 0},
+		},
+		{
+			name: "replace drops deleted jobs but keeps assumptions",
+			run: func(c *allocationCache) {
+				c.observe(store.JobUpdate, cacheJob("gone", 6, model.JobRunning, "n1", 100))
+				c.assume(cacheJob("a", 7, model.JobPending, "", 200), "n1")
+				c.replace([]*model.Job{cacheJob("b", 8, model.JobScheduled, "n2", 300)})
+			},
+			want: map[string]int64{"n1": 200, "n2": 300},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newAllocationCache()
+			tt.run(c)
+			for nodeID, want := range tt.want {
+				if got := c.allocated(nodeID).MilliCPU; got != want {
+					t.Errorf("allocated(%s) = %d, want %d", nodeID, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// depStatus 任务的依赖是否满足
+type depStatus int
+
+const (
+	depReady   depStatus = iota // 所有依赖都已 Success，可以调度
+	depWaiting                  // 还有依赖没有结束，暂停调度
+	depFailed                   // 有依赖失败/取消/跳过/不存在，按 DependencyPolicy 处理
+)
+
+// dependencyTracker 实现 DAG 调度
+// 记录所有任务的最新状态；依赖没有完成的 Pending 任务暂存在 waiting 里 (不占用调度队列)，
+// 等某个依赖结束时再放回队列重新评估
+type dependencyTracker struct {
+	mu      sync.Mutex
+	states  map[string]model.JobState // jobID -> 最新状态
+	waiting map[string]*model.Job     // jobID -> 等待依赖的任务
+}
+
+func newDependencyTracker() *dependencyTracker {
+	return &dependencyTracker{
+		states:  make(map[string]model.JobState),
+		waiting: make(map[string]*model.Job),
+	}
+}
+
+// check 判断任务的依赖是否满足，还没满足时把任务登记到 waiting
+// 判断和登记在同一把锁里完成，不会错过两者之间到达的依赖结束事件
+// 返回 depFailed 时 reason 描述是哪个依赖出了问题
+func (t *dependencyTracker) check(job *model.Job) (status depStatus, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status = depReady
+	for _, dep := range job.Dependencies {
+		state, ok := t.states[dep]
+		switch {
+		case !ok:
+			return depFailed, fmt.Sprintf("dependency %s not found", dep)
+		case state == model.JobSuccess:
+		case state.IsTerminal():
+			return depFailed, fmt.Sprintf("dependency %s is %s", dep, state)
+		default:
+			status = depWaiting
+		}
+	}
+
+	if status == depWaiting {
+		t.waiting[job.ID] = job
+	}
+	return status, ""
+}
+
+// observe 记录任务的最新状态
+// 任务结束 (或被删除) 时返回等待它的任务，调用方把它们放回调度队列重新评估
+func (t *dependencyTracker) observe(eventType store.JobEventType, job *model.Job) []*model.Job {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if eventType == store.JobDelete {
+		delete(t.states, job.ID)
+		delete(t.waiting, job.ID)
+	} else {
+		t.states[job.ID] = job.Status.State
+		if job.Status.State != model.JobPending {
+			delete(t.waiting, job.ID) // 等待中的任务被取消了
+		}
+		if !job.Status.State.IsTerminal() {
+			return nil
+		}
+	}
+
+	var ready []*model.Job
+	for id, waiting := range t.waiting {
+		for _, dep := range waiting.Dependencies {
+			if dep == job.ID {
+				ready = append(ready, waiting)
+				delete(t.waiting, id)
+				break
+			}
+		}
+	}
+	return ready
+}
+
+// replace 重新 List 之后用全量数据重建
+// 所有 Pending 任务都会重新入队，waiting 随之清空，由调度协程重新判断
+func (t *dependencyTracker) replace(jobs []*model.Job) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.states = make(map[string]model.JobState, len(jobs))
+	for _, job := range jobs {
+		t.states[job.ID] = job.Status.State
+	}
+	t.waiting = make(map[string]*model.Job)
+}
@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"log"
+	"time"
+
 	"titan/pkg/model"
 )
 
@@ -24,7 +26,19 @@ func (s *Scheduler) checkNode(job *model.Job, node *model.Node) bool {
 		return false
 	}
 
-	// 2. 资源检查 (CPU & Memory)
+	// 心跳已过期但 NodeController 还没来得及标记 OFFLINE 的节点，同样不能调度
+	if node.IsStale(time.Now()) {
+		log.Printf("[Filter] Node %s filtered: Heartbeat timeout", node.ID)
+		return false
+	}
+
+	// 2. 节点要有对应的执行器 (比如没有 Docker 的节点不能运行 DOCKER 任务)
+	if !node.Supports(job.Type) {
+		log.Printf("[Filter] Node %s filtered: Cannot run %s jobs (supports: %v)", node.ID, job.Type, node.JobTypes)
+		return false
+	}
+
+	// 3. 资源检查 (CPU & Memory)
 	// 计算剩余资源 = 总容量 - 已分配
 	freeCpu := node.TotalCap.MilliCPU - node.Allocated.MilliCPU
 	freeMem := node.TotalCap.Memory - node.Allocated.Memory
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+func readyNode(id string, cpu, mem int64, allocCPU, allocMem int64, types ...model.JobType) *model.Node {
+	return &model.Node{
+		ID:            id,
+		Status:        model.NodeReady,
+		LastHeartbeat: time.Now().Unix(),
+		TotalCap:      model.Resource{MilliCPU: cpu, Memory: mem},
+		Allocated:     model.Resource{MilliCPU: allocCPU, Memory: allocMem},
+		JobTypes:      types,
+	}
+}
+
+func TestFilterNodes(t *testing.T) {
+	offline := readyNode("offline", 4000, 4096, 0, 0)
+	offline.Status = model.NodeOffline
+	stale := readyNode("stale", 4000, 4096, 0, 0)
+	stale.LastHeartbeat = time.Now().Add(-2 * model.NodeHeartbeatTimeout).Unix()
+
+	nodes := []*model.Node{
+		readyNode("idle", 4000, 4096, 0, 0, model.JobTypeShell, model.JobTypeDocker),
+		readyNode("busy", 4000, 4096, 3500, 0),
+		readyNode("full-mem", 4000, 4096, 0, 4000),
+		readyNode("shell-only", 4000, 4096, 0, 0, model.JobTypeShell),
+		offline,
+		stale,
+	}
+	tests := []struct {
+		name string
+		job  *model.Job
+		want []string
+	}{
+		{
+			name: "shell job fits",
+			job:  &model.Job{Type: model.JobTypeShell, ResReq: model.Resource{MilliCPU: 500, Memory: 97}},
+			want: []string{"idle", "busy", "shell-only"},
+		},
+		{
+			name: "untyped job counts as shell",
+			job:  &model.Job{ResReq: model.Resource{MilliCPU: 1000, Memory: 1024}},
+			want: []string{"idle", "shell-only"},
+		},
+		{
+			name: "docker job needs a docker executor",
+			job:  &model.Job{Type: model.JobTypeDocker, ResReq: model.Resource{MilliCPU: 100, Memory: 64}},
+			want: []string{"idle"},
+		},
+		{
+			name: "exactly the free resources",
+			job:  &model.Job{ResReq: model.Resource{MilliCPU: 500, Memory: 96}},
+			want: []string{"idle", "busy", "full-mem", "shell-only"},
+		},
+		{
+			name: "too big for any node",
+			job:  &model.Job{ResReq: model.Resource{MilliCPU: 5000, Memory: 1}},
+			want: []string{},
+		},
+	}
+	s := NewScheduler(store.NewMemoryStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]string, 0)
+			for _, node := range s.filterNodes(tt.job, nodes) {
+				ids = append(ids, node.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("filterNodes() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
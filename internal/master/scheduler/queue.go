@@ -0,0 +1,221 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"titan/pkg/model"
+)
+
+const (
+	// 调度失败后的退避时间：1s, 2s, 4s ... 最长 maxBackoff
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 30 * time.Second
+
+	// 不可调度的任务最多等待这么久就会被强制重新评估 (兜底：防止漏掉集群事件)
+	unschedulableTimeout = 60 * time.Second
+)
+
+// queuedJob 队列中的一个待调度任务
+type queuedJob struct {
+	job          *model.Job
+	attempts     int       // 已经失败的调度次数
+	backoffUntil time.Time // 在此之前不允许再次调度
+	parkedAt     time.Time // 进入 unschedulable 区的时间
+}
+
+// schedulingQueue 调度队列 (参考 Kubernetes 的 PriorityQueue 设计，去掉了优先级)
+//   - active:        可以立即调度的任务 (FIFO)
+//   - backoff:       刚调度失败、正在退避的任务，退避结束后自动回到 active
+//   - unschedulable: 当前集群放不下的任务，等节点加入/更新、任务结束释放资源时再重新评估
+type schedulingQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	active        []*queuedJob
+	backoff       map[string]*queuedJob
+	unschedulable map[string]*queuedJob
+	closed        bool
+}
+
+func newSchedulingQueue() *schedulingQueue {
+	q := &schedulingQueue{
+		backoff:       make(map[string]*queuedJob),
+		unschedulable: make(map[string]*queuedJob),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Add 新的 (或被更新的) Pending 任务直接进入 active
+// 失败重试的任务在 Status.NextRetryTime 之前先进入 backoff 区
+// 如果任务已经在退避/不可调度区，只刷新任务内容，保留原有的退避状态
+func (q *schedulingQueue) Add(job *model.Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if qj, ok := q.backoff[job.ID]; ok {
+		qj.job = job
+		return
+	}
+	if qj, ok := q.unschedulable[job.ID]; ok {
+		qj.job = job
+		return
+	}
+	for _, qj := range q.active {
+		if qj.job.ID == job.ID {
+			qj.job = job
+			return
+		}
+	}
+	qj := &queuedJob{job: job}
+	if time.Now().Before(job.Status.NextRetryTime) {
+		qj.backoffUntil = job.Status.NextRetryTime
+		q.backoff[job.ID] = qj
+		return
+	}
+	q.pushActiveLocked(qj)
+}
+
+// Pop 阻塞直到有可调度的任务；队列关闭后返回 false
+func (q *schedulingQueue) Pop() (*queuedJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.active) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+	qj := q.active[0]
+	q.active = q.active[1:]
+	return qj, true
+}
+
+// AddUnschedulable 集群当前放不下这个任务：记录失败次数并等待集群事件
+func (q *schedulingQueue) AddUnschedulable(qj *queuedJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.trackedLocked(qj.job.ID) {
+		// 调度期间又收到了新的事件，以新入队的为准
+		return
+	}
+	now := time.Now()
+	q.recordFailureLocked(qj, now)
+	qj.parkedAt = now
+	q.unschedulable[qj.job.ID] = qj
+}
+
+// AddBackoff 调度过程出错 (比如 Store 暂时不可用)：退避后直接重试，不需要等集群事件
+func (q *schedulingQueue) AddBackoff(qj *queuedJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.trackedLocked(qj.job.ID) {
+		return
+	}
+	q.recordFailureLocked(qj, time.Now())
+	q.backoff[qj.job.ID] = qj
+}
+
+// Delete 任务已经不再是 Pending (被绑定、取消或删除)，从队列中移除
+func (q *schedulingQueue) Delete(jobID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.backoff, jobID)
+	delete(q.unschedulable, jobID)
+	for i, qj := range q.active {
+		if qj.job.ID == jobID {
+			q.active = append(q.active[:i], q.active[i+1:]...)
+			break
+		}
+	}
+}
+
+// MoveAllToActive 集群发生了可能腾出资源的事件 (节点加入/更新、任务结束)
+// 不可调度区的任务全部重新评估：退避已结束的进入 active，否则进入 backoff 等待
+func (q *schedulingQueue) MoveAllToActive() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for id, qj := range q.unschedulable {
+		delete(q.unschedulable, id)
+		if now.Before(qj.backoffUntil) {
+			q.backoff[id] = qj
+		} else {
+			q.pushActiveLocked(qj)
+		}
+	}
+}
+
+// flush 周期性调用：把退避结束的任务、以及等待过久的不可调度任务放回 active
+func (q *schedulingQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for id, qj := range q.backoff {
+		if !now.Before(qj.backoffUntil) {
+			delete(q.backoff, id)
+			q.pushActiveLocked(qj)
+		}
+	}
+	for id, qj := range q.unschedulable {
+		if now.Sub(qj.parkedAt) >= unschedulableTimeout {
+			delete(q.unschedulable, id)
+			q.pushActiveLocked(qj)
+		}
+	}
+}
+
+// Close 唤醒所有阻塞在 Pop 上的协程
+func (q *schedulingQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// Len 队列中的任务总数 (用于日志/观测)
+func (q *schedulingQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.active) + len(q.backoff) + len(q.unschedulable)
+}
+
+func (q *schedulingQueue) pushActiveLocked(qj *queuedJob) {
+	q.active = append(q.active, qj)
+	q.cond.Signal()
+}
+
+func (q *schedulingQueue) trackedLocked(jobID string) bool {
+	if _, ok := q.backoff[jobID]; ok {
+		return true
+	}
+	if _, ok := q.unschedulable[jobID]; ok {
+		return true
+	}
+	for _, qj := range q.active {
+		if qj.job.ID == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFailureLocked 失败次数 +1，并计算指数退避的截止时间
+func (q *schedulingQueue) recordFailureLocked(qj *queuedJob, now time.Time) {
+	qj.attempts++
+	backoff := initialBackoff
+	for i := 1; i < qj.attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	qj.backoffUntil = now.Add(backoff)
+}
@@ -0,0 +1,209 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+)
+
+func pendingJob(id string) *model.Job {
+	job := &model.Job{ID: id}
+	job.Status.State = model.JobPending
+	return job
+}
+
+// queueState 任务当前在队列的哪个区
+func queueState(q *schedulingQueue, jobID string) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.backoff[jobID]; ok {
+		return "backoff"
+	}
+	if _, ok := q.unschedulable[jobID]; ok {
+		return "unschedulable"
+	}
+	for _, qj := range q.active {
+		if qj.job.ID == jobID {
+			return "active"
+		}
+	}
+	return "none"
+}
+
+func TestSchedulingQueueBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 1 * time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, maxBackoff},
+		{20, maxBackoff},
+	}
+	q := newSchedulingQueue()
+	now := time.Now()
+	for _, tt := range tests {
+		qj := &queuedJob{attempts: tt.attempts - 1}
+		q.recordFailureLocked(qj, now)
+		if qj.attempts != tt.attempts {
+			t.Errorf("attempts = %d, want %d", qj.attempts, tt.attempts)
+		}
+		if got := qj.backoffUntil.Sub(now); got != tt.want {
+			t.Errorf("backoff after %d failures = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulingQueue(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(q *schedulingQueue) string // 返回任务 "job" 最后所在的区
+		want string
+	}{
+		{
+			name: "new job is active",
+			run: func(q *schedulingQueue) string {
+				q.Add(pendingJob("job"))
+				return queueState(q, "job")
+			},
+			want: "active",
+		},
+		{
+			name: "retry waits for NextRetryTime",
+			run: func(q *schedulingQueue) string {
+				job := pendingJob("job")
+				job.Status.NextRetryTime = time.Now().Add(time.Minute)
+				q.Add(job)
+				q.flush()
+				return queueState(q, "job")
+			},
+			want: "backoff",
+		},
+		{
+			name: "backoff ends on flush",
+			run: func(q *schedulingQueue) string {
+				q.AddBackoff(&queuedJob{job: pendingJob("job")})
+				q.backoff["job"].backoffUntil = time.Now().Add(-time.Millisecond)
+				q.flush()
+				return queueState(q, "job")
+			},
+			want: "active",
+		},
+		{
+			name: "unschedulable waits for a cluster event",
+			run: func(q *schedulingQueue) string {
+				q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+				q.unschedulable["job"].backoffUntil = time.Now().Add(-time.Millisecond)
+				q.flush()
+				return queueState(q, "job")
+			},
+			want: "unschedulable",
+		},
+		{
+			name: "cluster event moves unschedulable to active",
+			run: func(q *schedulingQueue) string {
+				q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+				q.unschedulable["job"].backoffUntil = time.Now().Add(-time.Millisecond)
+				q.MoveAllToActive()
+				return queueState(q, "job")
+			},
+			want: "active",
+		},
+		{
+			name: "cluster event during backoff keeps backing off",
+			run: func(q *schedulingQueue) string {
+				q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+				q.MoveAllToActive()
+				return queueState(q, "job")
+			},
+			want: "backoff",
+		},
+		{
+			name: "unschedulable timeout",
+			run: func(q *schedulingQueue) string {
+				q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+				q.unschedulable["job"].parkedAt = time.Now().Add(-unschedulableTimeout)
+				q.flush()
+				return queueState(q, "job")
+			},
+			want: "active",
+		},
+		{
+			name: "newer event wins over a failed attempt",
+			run: func(q *schedulingQueue) string {
+				q.Add(pendingJob("job"))
+				qj, _ := q.Pop()
+				q.Add(pendingJob("job")) // 调度期间任务又被更新
+				q.AddUnschedulable(qj)
+				return queueState(q, "job")
+			},
+			want: "active",
+		},
+		{
+			name: "delete",
+			run: func(q *schedulingQueue) string {
+				q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+				q.Delete("job")
+				return queueState(q, "job")
+			},
+			want: "none",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newSchedulingQueue()
+			if got := tt.run(q); got != tt.want {
+				t.Errorf("job is %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulingQueueAddKeepsBackoff(t *testing.T) {
+	q := newSchedulingQueue()
+	q.AddUnschedulable(&queuedJob{job: pendingJob("job")})
+	q.AddUnschedulable(&queuedJob{job: pendingJob("other"), attempts: 2})
+
+	updated := pendingJob("job")
+	updated.Name = "updated"
+	q.Add(updated)
+
+	qj := q.unschedulable["job"]
+	if qj.job.Name != "updated" || qj.attempts != 1 {
+		t.Fatalf("Add() on a parked job: name %q attempts %d, want the new job and the old attempts", qj.job.Name, qj.attempts)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", q.Len())
+	}
+}
+
+func TestSchedulingQueuePopFIFOAndClose(t *testing.T) {
+	q := newSchedulingQueue()
+	for _, id := range []string{"a", "b", "c"} {
+		q.Add(pendingJob(id))
+	}
+	for _, want := range []string{"a", "b", "c"} {
+		qj, ok := q.Pop()
+		if !ok || qj.job.ID != want {
+			t.Fatalf("Pop() = %v, %v, want %s", qj, ok, want)
+		}
+	}
+
+	done := make(chan bool)
+	go func() {
+		_, ok := q.Pop()
+		done <- ok
+	}()
+	q.Close()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Fatal("Pop() after Close() returned a job")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Pop() did not return after Close()")
+	}
+}
@@ -2,41 +2,136 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"titan/pkg/model"
 	"titan/pkg/store"
 )
 
+// scheduleWorkers 并发执行调度的协程数
+const scheduleWorkers = 16
+
+// queueFlushInterval 检查退避到期任务的周期
+const queueFlushInterval = 1 * time.Second
+
 // Scheduler 核心调度器结构体
 type Scheduler struct {
 	store store.Store // 依赖 Store 接口操作 Etcd
+	cache *allocationCache
+	queue *schedulingQueue
+	deps  *dependencyTracker
+
+	// 串行化 "计算剩余资源 -> Filter -> Score -> 预占" 这一段决策，防止并发超卖
+	mu sync.Mutex
+
+	// 节点缓存 (List + WatchNodes 维护)，调度决策只读这里，不在 mu 里访问 Store
+	// 也用来判断节点容量/状态是否真正变化，只有变化时才触发重新评估 (忽略普通心跳)
+	nodesMu sync.RWMutex
+	nodes   map[string]*model.Node
+
+	// 绑定 / 取消之后通知 Worker，为 nil 时 Worker 只能通过 Watch 发现
+	dispatcher Dispatcher
+}
+
+// Dispatcher 把调度结果直接推送给 Worker
+// 推送是尽力而为的：失败时 Worker 仍然会通过 Watch 领取任务、停止被取消的任务，
+// 所以实现不能阻塞调度流程
+type Dispatcher interface {
+	StartJob(node *model.Node, job *model.Job)
+	StopJob(nodeID, jobID string)
 }
 
 // NewScheduler 构造函数
 func NewScheduler(s store.Store) *Scheduler {
 	return &Scheduler{
 		store: s,
+		cache: newAllocationCache(),
+		queue: newSchedulingQueue(),
+		deps:  newDependencyTracker(),
+		nodes: make(map[string]*model.Node),
 	}
 }
 
+// SetDispatcher 设置推送方式，必须在 Run 之前调用
+func (s *Scheduler) SetDispatcher(d Dispatcher) {
+	s.dispatcher = d
+}
+
 // Run 启动调度主循环 (这是后台常驻 Goroutine)
 func (s *Scheduler) Run(ctx context.Context) {
-	// 1. Watch 机制：监听 Job 变化 (简历加分项：事件驱动架构)
-	jobEventCh := s.store.WatchJobs(ctx)
+	// 1. List：启动时先全量读取一次任务
+	// Master 宕机期间提交的 Pending 任务在这里入队，已绑定的任务用来初始化资源账本
+	revision, err := s.resync(ctx)
+	if err != nil {
+		return
+	}
+
+	// 2. Watch 机制：从 List 的下一个 Revision 开始监听，List 和 Watch 之间不丢事件 (简历加分项：事件驱动架构)
+	jobEventCh := s.store.WatchJobs(ctx, revision+1)
+
+	// 节点先 Watch 再 List：两者之间的变化会在 List 之后按顺序重放，缓存最终是一致的
+	nodeEventCh := s.store.WatchNodes(ctx)
+	s.resyncNodes(ctx)
+
+	// 3. 启动调度协程，从队列中取任务
+	for i := 0; i < scheduleWorkers; i++ {
+		go s.worker(ctx)
+	}
+	defer s.queue.Close()
+
+	flushTicker := time.NewTicker(queueFlushInterval)
+	defer flushTicker.Stop()
+
+	// 节点 Watch 终止后等一会儿再重新建立 (Store 不可用时不能原地打转)，期间继续处理任务事件
+	var nodeRewatch <-chan time.Time
+	nodeRetries := 0
 
 	log.Println("[Scheduler] Started, watching for new jobs...")
 
 	for {
 		select {
-		case event := <-jobEventCh:
-			// 只处理 Pending (待调度) 的任务
-			if event.Job.Status.State == model.JobPending {
-				log.Printf("[Scheduler] Detected new job: %s", event.Job.ID)
-				// 异步调度，防止阻塞主 Watch 循环
-				go s.scheduleOne(ctx, event.Job)
+		case event, ok := <-jobEventCh:
+			if !ok || event.Err != nil {
+				// Watch 终止 (Revision 被压缩 / 重试耗尽)：重新 List 再 Watch
+				if ctx.Err() != nil {
+					log.Println("[Scheduler] Stopped.")
+					return
+				}
+				log.Printf("[Scheduler] Job watch terminated (%v), relisting...", watchErr(event, ok))
+				if revision, err = s.resync(ctx); err != nil {
+					continue
+				}
+				jobEventCh = s.store.WatchJobs(ctx, revision+1)
+				continue
+			}
+			s.handleJobEvent(event)
+		case event, ok := <-nodeEventCh:
+			if !ok || event.Err != nil {
+				if ctx.Err() != nil {
+					log.Println("[Scheduler] Stopped.")
+					return
+				}
+				delay := rewatchDelay(nodeRetries)
+				nodeRetries++
+				log.Printf("[Scheduler] Node watch terminated (%v), re-watching in %s", nodeWatchErr(event, ok), delay)
+				nodeEventCh = nil
+				nodeRewatch = time.After(delay)
+				continue
 			}
+			nodeRetries = 0
+			s.handleNodeEvent(event)
+		case <-nodeRewatch:
+			// 节点 Watch 重新建立，重新 List 补上中断期间的变化，再重新评估一次排队中的任务
+			nodeRewatch = nil
+			nodeEventCh = s.store.WatchNodes(ctx)
+			s.resyncNodes(ctx)
+			s.queue.MoveAllToActive()
+		case <-flushTicker.C:
+			s.queue.flush()
 		case <-ctx.Done():
 			log.Println("[Scheduler] Stopped.")
 			return
@@ -44,32 +139,266 @@ func (s *Scheduler) Run(ctx context.Context) {
 	}
 }
 
-// scheduleOne 执行单次调度逻辑
-func (s *Scheduler) scheduleOne(ctx context.Context, job *model.Job) {
-	// Step 1: 获取当前集群所有节点快照
+// resync 全量 List 任务，重建资源账本和调度队列，返回读取时的 Revision
+func (s *Scheduler) resync(ctx context.Context) (int64, error) {
+	jobList, err := s.listJobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.cache.replace(jobList.Jobs)
+	s.deps.replace(jobList.Jobs)
+	for _, job := range jobList.Jobs {
+		if job.Status.State == model.JobPending {
+			s.queue.Add(job)
+		} else {
+			s.queue.Delete(job.ID)
+		}
+	}
+	log.Printf("[Scheduler] Reconciled %d jobs at revision %d (queued: %d)",
+		len(jobList.Jobs), jobList.Revision, s.queue.Len())
+	return jobList.Revision, nil
+}
+
+// watchErr 描述 Watch 终止的原因
+func watchErr(event store.JobEvent, ok bool) error {
+	if !ok {
+		return errors.New("watch channel closed")
+	}
+	return event.Err
+}
+
+// nodeWatchErr 描述节点 Watch 终止的原因
+func nodeWatchErr(event store.NodeEvent, ok bool) error {
+	if !ok {
+		return errors.New("watch channel closed")
+	}
+	return event.Err
+}
+
+// rewatchDelay 第 retries+1 次重新建立 Watch 之前的等待时间：initialBackoff 起逐次加倍，最长 maxBackoff
+func rewatchDelay(retries int) time.Duration {
+	delay := initialBackoff
+	for i := 0; i < retries && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// listJobs 读取全部任务，失败时重试直到成功或 ctx 结束
+func (s *Scheduler) listJobs(ctx context.Context) (*store.JobList, error) {
+	for {
+		jobList, err := s.store.ListJobs(ctx, nil)
+		if err == nil {
+			return jobList, nil
+		}
+		log.Printf("[Error] Failed to list jobs, retrying: %v", err)
+
+		select {
+		case <-time.After(initialBackoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// handleJobEvent 处理任务变化
+func (s *Scheduler) handleJobEvent(event store.JobEvent) {
+	// 已经分配到节点上的任务被取消 / 删除：通知节点停止执行
+	// 只看账本中还占用资源的任务，同一个任务只会通知一次
+	if s.dispatcher != nil && (event.Type == store.JobDelete || event.Job.Status.State == model.JobCancelled) {
+		if nodeID, ok := s.cache.nodeOf(event.Job.ID); ok {
+			s.dispatcher.StopJob(nodeID, event.Job.ID)
+		}
+	}
+
+	// 任务绑定 / 结束都会引起资源变化，先更新账本
+	s.cache.observe(event.Type, event.Job)
+
+	// 上游任务结束：等待它的下游任务重新评估依赖
+	for _, job := range s.deps.observe(event.Type, event.Job) {
+		s.queue.Add(job)
+	}
+
+	// 只有 Pending (待调度) 的任务进入队列
+	if event.Type != store.JobDelete && event.Job.Status.State == model.JobPending {
+		log.Printf("[Scheduler] Detected new job: %s", event.Job.ID)
+		s.queue.Add(event.Job)
+		return
+	}
+
+	s.queue.Delete(event.Job.ID)
+
+	// 任务结束 / 被删除会释放资源，排队中的任务可能放得下了
+	if event.Type == store.JobDelete || event.Job.Status.State.IsTerminal() {
+		s.queue.MoveAllToActive()
+	}
+}
+
+// resyncNodes 全量 List 节点，重建节点缓存
+// 失败时保留原有缓存：节点的心跳会陆续把它补上，心跳过期的节点本来就不会被选中
+func (s *Scheduler) resyncNodes(ctx context.Context) {
 	nodes, err := s.store.ListNodes(ctx)
 	if err != nil {
 		log.Printf("[Error] Failed to list nodes: %v", err)
 		return
 	}
 
-	// Step 2: Filter (过滤) - 剔除资源不足的节点
-	candidates := s.filterNodes(job, nodes)
-	if len(candidates) == 0 {
-		log.Printf("[Failed] Job %s pending: no suitable nodes found", job.ID)
+	s.nodesMu.Lock()
+	defer s.nodesMu.Unlock()
+	s.nodes = make(map[string]*model.Node, len(nodes))
+	for _, node := range nodes {
+		s.nodes[node.ID] = node
+	}
+}
+
+// handleNodeEvent 处理节点变化：更新节点缓存，新节点加入、节点恢复或扩容时重新评估排队中的任务
+func (s *Scheduler) handleNodeEvent(event store.NodeEvent) {
+	node := event.Node
+	s.nodesMu.Lock()
+	old, known := s.nodes[node.ID]
+	if event.Type == store.NodeDelete {
+		delete(s.nodes, node.ID)
+	} else {
+		s.nodes[node.ID] = node
+	}
+	s.nodesMu.Unlock()
+
+	if event.Type == store.NodeDelete {
 		return
 	}
+	if known && old.Status == node.Status && old.TotalCap == node.TotalCap {
+		return // 普通心跳，没有带来新的容量
+	}
 
-	// Step 3: Score (打分) - 选出最优节点 (Bin-packing 策略)
-	bestNode := s.scoreNodes(job, candidates)
+	if node.Status == model.NodeReady {
+		log.Printf("[Scheduler] Node %s is ready, re-evaluating pending jobs", node.ID)
+		s.queue.MoveAllToActive()
+	}
+}
+
+// worker 不断从队列中取出任务并调度
+func (s *Scheduler) worker(ctx context.Context) {
+	for {
+		qj, ok := s.queue.Pop()
+		if !ok {
+			return
+		}
+		s.scheduleOne(ctx, qj)
+	}
+}
+
+// scheduleOne 执行单次调度逻辑
+func (s *Scheduler) scheduleOne(ctx context.Context, qj *queuedJob) {
+	job := qj.job
+
+	// Step 0: DAG 依赖检查 - 上游任务全部 Success 才能调度
+	if len(job.Dependencies) > 0 {
+		switch status, reason := s.deps.check(job); status {
+		case depWaiting:
+			log.Printf("[Scheduler] Job %s waiting for dependencies %v", job.ID, job.Dependencies)
+			return
+		case depFailed:
+			s.abandon(ctx, qj, reason)
+			return
+		}
+	}
+
+	bestNode := s.selectNode(job)
+	if bestNode == nil {
+		// 集群当前放不下：放入不可调度区，等资源释放 / 新节点加入
+		log.Printf("[Failed] Job %s pending: no suitable nodes found (attempt %d, queued jobs: %d)",
+			job.ID, qj.attempts+1, s.queue.Len())
+		s.queue.AddUnschedulable(qj)
+		return
+	}
 
 	// Step 4: Bind (绑定) - 将决策写入 Etcd
-	err = s.bind(ctx, job, bestNode.ID)
+	baseVersion := job.ResourceVersion
+	err := s.bind(ctx, job, bestNode.ID)
 	if err != nil {
+		// 绑定失败，撤销这一次的预占
+		s.cache.forget(job.ID, bestNode.ID, baseVersion)
+		if store.IsConflict(err) {
+			// 任务在读取之后被修改过 (被别的调度协程/Master 抢先绑定，或被取消)
+			// 以最新版本为准，新的 Watch 事件会更新账本并触发下一轮调度
+			log.Printf("[Scheduler] Job %s changed since it was read, skip binding", job.ID)
+			return
+		}
 		log.Printf("[Error] Failed to bind job %s to node %s: %v", job.ID, bestNode.ID, err)
+		s.queue.AddBackoff(qj)
 	} else {
 		log.Printf("[Success] Scheduled Job %s -> Node %s", job.ID, bestNode.ID)
+		if s.dispatcher != nil {
+			s.dispatcher.StartJob(bestNode, job)
+		}
+	}
+}
+
+// abandon 上游依赖失败，按任务的 DependencyPolicy 把它标记为 Failed 或 Skipped
+// 下游任务看到它结束后会继续按自己的策略处理，失败沿着 DAG 传递下去
+func (s *Scheduler) abandon(ctx context.Context, qj *queuedJob, reason string) {
+	job := qj.job
+	if job.DependencyPolicy == model.DependencySkip {
+		job.Status.State = model.JobSkipped
+	} else {
+		job.Status.State = model.JobFailed
+	}
+	job.Status.Error = reason
+	job.Status.Reason = model.ReasonDependencyFailed
+	job.Status.EndTime = time.Now()
+
+	err := s.store.CompareAndSwapJob(ctx, job)
+	switch {
+	case err == nil:
+		log.Printf("[Scheduler] Job %s marked %s: %s", job.ID, job.Status.State, reason)
+	case store.IsConflict(err):
+		log.Printf("[Scheduler] Job %s changed since it was read, skip", job.ID)
+	default:
+		log.Printf("[Error] Failed to update job %s: %v", job.ID, err)
+		s.queue.AddBackoff(qj)
+	}
+}
+
+// selectNode 选出最优节点并预占资源
+// 返回 nil 表示当前没有满足条件的节点
+func (s *Scheduler) selectNode(job *model.Job) *model.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Step 1: 获取当前集群所有节点快照 (来自节点缓存，不访问 Store)
+	// 已分配资源以调度器的账本为准，不信任节点记录里的值
+	nodes := s.nodeSnapshot()
+	for _, node := range nodes {
+		node.Allocated = s.cache.allocated(node.ID)
+	}
+
+	// Step 2: Filter (过滤) - 剔除资源不足的节点
+	candidates := s.filterNodes(job, nodes)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Step 3: Score (打分) - 选出最优节点 (Bin-packing 策略)
+	bestNode := s.scoreNodes(job, candidates)
+
+	// 在锁内预占资源，下一个任务看到的就是扣减后的剩余量
+	s.cache.assume(job, bestNode.ID)
+	return bestNode
+}
+
+// nodeSnapshot 节点缓存的副本，按 ID 排序 (和 ListNodes 的顺序一致)
+func (s *Scheduler) nodeSnapshot() []*model.Node {
+	s.nodesMu.RLock()
+	defer s.nodesMu.RUnlock()
+
+	nodes := make([]*model.Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		n := *node
+		nodes = append(nodes, &n)
 	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
 }
 
 // bind 将调度结果持久化
@@ -78,6 +407,6 @@ func (s *Scheduler) bind(ctx context.Context, job *model.Job, nodeID string) err
 	job.Status.NodeID = nodeID
 	job.Status.StartTime = time.Now()
 
-	// 更新 Etcd 中的任务状态
-	return s.store.UpdateJob(ctx, job)
+	// 基于 ResourceVersion 的 CAS 写入：同一个任务只会被成功绑定一次
+	return s.store.CompareAndSwapJob(ctx, job)
 }
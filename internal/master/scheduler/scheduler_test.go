@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// countingNodeStore 统计 ListNodes 调用次数
+type countingNodeStore struct {
+	*store.MemoryStore
+	lists atomic.Int64
+}
+
+func (s *countingNodeStore) ListNodes(ctx context.Context) ([]*model.Node, error) {
+	s.lists.Add(1)
+	return s.MemoryStore.ListNodes(ctx)
+}
+
+func TestSelectNodeUsesNodeCache(t *testing.T) {
+	ctx := context.Background()
+	st := &countingNodeStore{MemoryStore: store.NewMemoryStore()}
+	if err := st.RegisterNode(ctx, readyNode("listed", 4000, 4096, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	s := NewScheduler(st)
+	s.resyncNodes(ctx)
+	s.handleNodeEvent(store.NodeEvent{Type: store.NodeUpdate, Node: readyNode("watched", 8000, 8192, 0, 0)})
+
+	job := &model.Job{ID: "job-1", ResReq: model.Resource{MilliCPU: 6000, Memory: 1024}}
+	if node := s.selectNode(job); node == nil || node.ID != "watched" {
+		t.Fatalf("selectNode() = %v, want watched", node)
+	}
+	// 缓存里的节点不能被 Allocated 的计算污染
+	if got := s.nodeSnapshot()[1].Allocated; got != (model.Resource{}) {
+		t.Errorf("cached node Allocated = %+v, want zero", got)
+	}
+
+	s.handleNodeEvent(store.NodeEvent{Type: store.NodeDelete, Node: &model.Node{ID: "listed"}})
+	small := &model.Job{ID: "job-2", ResReq: model.Resource{MilliCPU: 1000, Memory: 1024}}
+	if node := s.selectNode(small); node == nil || node.ID != "watched" {
+		t.Errorf("selectNode() after delete = %v, want watched", node)
+	}
+	if n := st.lists.Load(); n != 1 {
+		t.Errorf("ListNodes() called %d times, want 1 (only the initial resync)", n)
+	}
+}
+
+// brokenNodeWatchStore 节点 Watch 一建立就失败 (比如 Etcd 不可用)
+type brokenNodeWatchStore struct {
+	*store.MemoryStore
+	watches atomic.Int64
+}
+
+func (s *brokenNodeWatchStore) WatchNodes(ctx context.Context) <-chan store.NodeEvent {
+	s.watches.Add(1)
+	ch := make(chan store.NodeEvent, 1)
+	ch <- store.NodeEvent{Err: errors.New("etcd unavailable")}
+	close(ch)
+	return ch
+}
+
+func TestSchedulerBacksOffNodeWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &brokenNodeWatchStore{MemoryStore: store.NewMemoryStore()}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewScheduler(s).Run(ctx)
+	}()
+
+	// 第一次重试在 initialBackoff 之后，第二次再等 2*initialBackoff
+	time.Sleep(initialBackoff + initialBackoff/2)
+	cancel()
+	<-done
+	if n := s.watches.Load(); n != 2 {
+		t.Errorf("WatchNodes() called %d times in %s, want 2", n, initialBackoff+initialBackoff/2)
+	}
+}
+
+func TestRewatchDelay(t *testing.T) {
+	tests := []struct {
+		retries int
+		want    time.Duration
+	}{
+		{0, initialBackoff},
+		{1, 2 * initialBackoff},
+		{3, 8 * initialBackoff},
+		{10, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := rewatchDelay(tt.retries); got != tt.want {
+			t.Errorf("rewatchDelay(%d) = %s, want %s", tt.retries, got, tt.want)
+		}
+	}
+}
@@ -2,41 +2,131 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/pb"
+	"titan/internal/auth"
 	"titan/internal/worker/executor"
+	"titan/pkg/logstore"
 	"titan/pkg/model"
 	"titan/pkg/store"
 )
 
+// heartbeatInterval 心跳 (租约续约) 周期，必须明显小于 store.NodeLeaseTTL
+const heartbeatInterval = 3 * time.Second
+
+// maxErrorLineLen 失败原因里附带的输出最多保留多少个字符
+const maxErrorLineLen = 200
+
+// errDeadlineExceeded 任务运行时间超过 Spec.ActiveDeadlineSeconds
+var errDeadlineExceeded = errors.New("deadline exceeded")
+
 type Agent struct {
-	ID       string
-	store    store.Store
-	executor *executor.DockerExecutor
+	ID string
+	IP string // 注册时上报的地址，Master 通过 IP + WorkerService 端口推送任务 (默认 127.0.0.1)
+
+	// WorkerService 的认证：只接受带有节点 Token 的请求，配置了证书时使用 TLS
+	Auth auth.Config
+
+	// 任务从 store 读取 (List + Watch)，状态和心跳通过 master 上报，由 Master 写入集群状态
+	store  store.Store
+	master pb.MasterServiceClient
+
+	// 本节点的资源总量 (注册时上报)
+	capacity model.Resource
+
+	// 任务输出写到 logs (可以和 store 是同一个)
+	// fs 日志后端时 logAddr 是本节点日志读取接口的地址，注册时上报为 Node.LogAddr
+	logs    logstore.LogStore
+	logAddr string
+
+	// WorkerService 的端口 (0 表示不提供)
+	port int
+
+	// 按任务类型选择执行器 (没有 Docker 的节点上不会有 JobTypeDocker)
+	executors map[model.JobType]executor.Executor
+
+	// 本节点正在执行的任务 (资源占用随心跳上报为 Node.Allocated)
+	mu      sync.Mutex
+	running map[string]*runningJob
 }
 
-func NewAgent(s store.Store) *Agent {
+// runningJob 一个正在本节点执行的任务
+type runningJob struct {
+	res     model.Resource
+	attempt int                // 第几次执行 (Status.Retries)
+	version int64              // 开始执行时任务的 ResourceVersion
+	cancel  context.CancelFunc // 停止任务 (任务被取消、删除或改派时调用)
+	logs    *logCollector      // 任务的输出 (开始执行之后才有)
+
+	finishing bool // 已经执行完、正在上报结果，之后看到的状态变化是自己造成的
+}
+
+func NewAgent(s store.Store, master pb.MasterServiceClient, logs logstore.LogStore) *Agent {
 	hostname, _ := os.Hostname()
 	if hostname == "" {
 		hostname = "worker-node-01"
 	}
 
-	// 初始化 Docker 执行器
-	exec, err := executor.NewDockerExecutor()
+	executors := make(map[model.JobType]executor.Executor)
+
+	// Shell 任务直接在宿主机上以子进程运行
+	procExec, err := executor.NewProcessExecutor(filepath.Join(os.TempDir(), "titan", "jobs"))
+	if err != nil {
+		log.Fatalf("Failed to init process executor: %v", err)
+	}
+	if err := procExec.EnableCgroups(executor.DefaultCgroupRoot); err != nil {
+		log.Printf("[Worker] ⚠️ cgroup v2 isolation disabled, shell jobs run without resource limits: %v", err)
+	}
+	executors[model.JobTypeShell] = procExec
+
+	// 初始化 Docker 执行器 (Docker 不可用时只能运行 Shell 任务)
+	dockerExec, err := executor.NewDockerExecutor()
 	if err != nil {
-		log.Fatalf("Failed to init docker executor: %v", err)
+		log.Printf("[Worker] ⚠️ Docker executor disabled, only shell jobs can run on this node: %v", err)
+	} else {
+		executors[model.JobTypeDocker] = dockerExec
 	}
 
-	return &Agent{
-		ID:       hostname,
-		store:    s,
-		executor: exec,
+	agent := &Agent{
+		ID:     hostname,
+		IP:     "127.0.0.1",
+		store:  s,
+		master: master,
+		// 这里恢复成真实的资源 (或者你之前修改过的 Mock 数据)
+		capacity: model.Resource{
+			MilliCPU: 4000,
+			Memory:   1024 * 1024 * 1024 * 8,
+		},
+		logs:      logs,
+		executors: executors,
+		running:   make(map[string]*runningJob),
+	}
+	if fs, ok := logs.(*logstore.FileStore); ok {
+		agent.logAddr = fs.Endpoint()
 	}
+	return agent
 }
 
-func (a *Agent) Run(ctx context.Context) {
+// Run 运行 Agent，直到 ctx 结束
+// lis 不为 nil 时在上面提供 WorkerService，Master 绑定任务后直接推送过来；
+// 无论是否提供，都会 Watch 分配给本节点的任务 (推送失败时的兜底)
+func (a *Agent) Run(ctx context.Context, lis net.Listener) {
+	if lis != nil {
+		a.port = lis.Addr().(*net.TCPAddr).Port
+		go a.serve(ctx, lis)
+	}
+
 	// 1. 启动心跳
 	go a.startHeartbeat(ctx)
 
@@ -46,78 +136,325 @@ func (a *Agent) Run(ctx context.Context) {
 }
 
 func (a *Agent) startHeartbeat(ctx context.Context) {
-	ticker := time.NewTicker(3 * time.Second)
+	ticker := time.NewTicker(heartbeatInterval)
 	defer ticker.Stop()
-	a.register(ctx)
+	registered := a.register(ctx)
 	for {
 		select {
 		case <-ticker.C:
-			a.register(ctx)
+			if !registered {
+				registered = a.register(ctx)
+			} else if !a.heartbeat(ctx) {
+				// Master 不认识本节点了 (比如 Master 重启过)，重新注册
+				registered = a.register(ctx)
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// watchJobs List + Watch 分配给本节点的任务
+// Watch 无法恢复 (比如 Revision 已被压缩) 时重新 List，保证不会漏掉任务
 func (a *Agent) watchJobs(ctx context.Context) {
-	eventCh := a.store.WatchJobs(ctx)
+	for ctx.Err() == nil {
+		revision, err := a.claimScheduledJobs(ctx)
+		if err != nil {
+			log.Printf("[Worker] Failed to list jobs: %v", err)
+			select {
+			case <-time.After(heartbeatInterval):
+			case <-ctx.Done():
+			}
+			continue
+		}
 
-	for event := range eventCh {
+		err = a.consumeJobEvents(ctx, revision+1)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[Worker] Job watch terminated (%v), relisting...", err)
+	}
+}
+
+// claimScheduledJobs 捡起已经分配给本节点、但还没开始执行的任务 (Worker 重启或 Watch 中断期间错过的)，
+// 同时停止 Watch 中断期间已经不归本节点的任务 (被取消、删除，或者节点失联时被 NodeController 改派)
+func (a *Agent) claimScheduledJobs(ctx context.Context) (int64, error) {
+	jobList, err := a.store.ListJobs(ctx, &store.JobFilter{
+		NodeID: a.ID,
+		States: []model.JobState{model.JobScheduled, model.JobRunning},
+	})
+	if err != nil {
+		return 0, err
+	}
+	assigned := make(map[string]*model.Job, len(jobList.Jobs))
+	for _, job := range jobList.Jobs {
+		assigned[job.ID] = job
+		if job.Status.State == model.JobScheduled && a.startJob(ctx, job) {
+			log.Printf("[Worker] ⚡ Found pending assignment: %s", job.ID)
+		}
+	}
+
+	a.mu.Lock()
+	var lost []string
+	for jobID, rj := range a.running {
+		// List 之后才推送过来的任务不在结果里，不能当成已经改派
+		if rj.finishing || rj.version > jobList.Revision {
+			continue
+		}
+		if job, ok := assigned[jobID]; !ok || job.Status.Retries != rj.attempt {
+			lost = append(lost, jobID)
+		}
+	}
+	a.mu.Unlock()
+	for _, jobID := range lost {
+		a.stopJob(jobID, "no longer assigned to this node")
+	}
+	return jobList.Revision, nil
+}
+
+// consumeJobEvents 处理 Watch 事件，直到 Watch 终止
+func (a *Agent) consumeJobEvents(ctx context.Context, fromRevision int64) error {
+	for event := range a.store.WatchJobs(ctx, fromRevision) {
+		if event.Err != nil {
+			return event.Err
+		}
 		job := event.Job
+		// 任务被取消或删除：停止本节点上对应的进程/容器
+		if event.Type == store.JobDelete || job.Status.State == model.JobCancelled {
+			a.stopJob(job.ID, "cancelled")
+			continue
+		}
+		// 本节点失联期间任务被改派 (退回 Pending / 标记失败 / 分配给其他节点)：停止本地的执行，不能跑两份
+		a.stopIfReassigned(job)
+
 		// 只有当任务被更新，且分配给我，且状态是 Scheduled 时，才处理
+		// Master 推送过来的任务已经在执行了，这里会被忽略
 		if job.Status.NodeID == a.ID && job.Status.State == model.JobScheduled {
-			log.Printf("[Worker] ⚡ Received job: %s", job.ID)
-			// 异步执行
-			go a.executeJob(ctx, job)
+			if a.startJob(ctx, job) {
+				log.Printf("[Worker] ⚡ Received job: %s", job.ID)
+			}
 		}
 	}
+	return errors.New("watch channel closed")
+}
+
+// startJob 异步执行任务，同一个任务已经在本节点执行时返回 false
+// 任务可能同时通过 Master 推送和 Watch 到达，只有先到的那一次会执行
+func (a *Agent) startJob(ctx context.Context, job *model.Job) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	if !a.trackRunning(job, cancel) {
+		cancel()
+		return false
+	}
+	go func() {
+		defer cancel()
+		defer a.untrackRunning(job.ID)
+		a.executeJob(ctx, job)
+	}()
+	return true
 }
 
 // executeJob 执行任务并更新状态 (关键修改在这里！)
 func (a *Agent) executeJob(ctx context.Context, job *model.Job) {
-	// 1. 更新状态为 Running
-	job.Status.State = model.JobRunning
-	a.store.UpdateJob(ctx, job)
+	// 1. 通知 Master 开始运行 (只能从 Scheduled 抢占一次，防止同一个任务被执行两遍)
+	// 返回 FAILED_PRECONDITION 说明任务已经被取消或改派，不能再执行
+	if _, err := a.reportStatus(ctx, job, &pb.UpdateJobStatusRequest{State: model.JobRunning.String()}); err != nil {
+		log.Printf("[Worker] Skip job %s: failed to mark running: %v", job.ID, err)
+		return
+	}
+
+	// 2. 按任务类型选择执行器并执行 (设置了 ActiveDeadlineSeconds 时超时就杀掉)
+	runCtx := ctx
+	if job.Spec.ActiveDeadlineSeconds > 0 {
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithTimeoutCause(ctx, activeDeadline(job), errDeadlineExceeded)
+		defer cancelRun()
+	}
+
+	// 输出边运行边写入 Store，运行中的任务也可以查看日志
+	var result *executor.Result
+	logs := newLogCollector(ctx, a.logs, job)
+	a.attachLogs(job.ID, logs)
+	exec, err := a.executorFor(job)
+	if err == nil {
+		result, err = exec.Run(runCtx, job, logs.Writer(model.StreamStdout), logs.Writer(model.StreamStderr))
+	}
+	// 先写完日志再写最终状态：用户看到任务结束时，日志一定已经可以查询
+	lastLine := logs.Close()
+	if ctx.Err() == nil && context.Cause(runCtx) == errDeadlineExceeded {
+		err = fmt.Errorf("%w: job did not finish within %s", errDeadlineExceeded, activeDeadline(job))
+	}
 
-	// 2. 调用 Docker 执行 (接收两个返回值：output 和 err)
-	output, err := a.executor.Run(ctx, job)
+	// ctx 被取消说明任务被取消/删除 (最终状态已经由取消方写好)，或者 Worker 正在退出 (交给 NodeController 处理)
+	// 两种情况下已经产生的日志都已保留，不再上报状态
+	jobCancelled := ctx.Err() != nil
+	ctx = context.WithoutCancel(ctx)
 
-	// 3. 根据结果更新最终状态
+	if jobCancelled {
+		log.Printf("[Worker] 🛑 Job %s stopped", job.ID)
+		return
+	}
+
+	// 3. 根据结果更新最终状态 (非零退出码 / OOM / 超时都算失败)
+	a.markFinishing(job.ID)
+	reason, failure := describeFailure(result, err, lastLine)
+	if failure != "" {
+		log.Printf("Job %s failed: %s", job.ID, failure)
+	}
+	req := &pb.UpdateJobStatusRequest{State: model.JobSuccess.String()}
+	if failure != "" {
+		req.State = model.JobFailed.String()
+		req.ErrorMessage = failure
+		req.Reason = reason
+	}
+	if result != nil {
+		req.ExitCode = int32(result.ExitCode)
+		req.PeakMemoryBytes = result.PeakMemory
+		req.CpuTimeMs = result.CPUTime.Milliseconds()
+	}
+	resp, err := a.reportStatus(ctx, job, req)
+	if status.Code(err) == codes.FailedPrecondition {
+		log.Printf("[Worker] Discarding result of job %s: %v", job.ID, err)
+		return
+	}
 	if err != nil {
-		log.Printf("Job failed: %v", err)
-		job.Status.State = model.JobFailed
-		job.Status.Error = err.Error()
-	} else {
-		job.Status.State = model.JobSuccess
+		log.Printf("[Worker] Failed to report result of job %s: %v", job.ID, err)
+		return
+	}
+	// 失败且还有重试次数的任务已被 Master 退回 Pending，由调度器在退避结束后重新调度
+	if resp.Retrying {
+		log.Printf("[Worker] ♻️ Job %s will be retried after %s (retry %d/%d)", job.ID,
+			(time.Duration(resp.RetryAfterMs) * time.Millisecond).Round(time.Second), job.Status.Retries+1, job.Spec.RetryCount)
+	}
+}
+
+// executorFor 根据 Job.Type 选择执行器 (未指定类型的任务按 Shell 处理)
+func (a *Agent) executorFor(job *model.Job) (executor.Executor, error) {
+	jobType := job.Type
+	if jobType == "" {
+		jobType = model.JobTypeShell
+	}
+	exec, ok := a.executors[jobType]
+	if !ok {
+		return nil, fmt.Errorf("node %s cannot run %s jobs (executor not available)", a.ID, jobType)
 	}
+	return exec, nil
+}
 
-	job.Status.EndTime = time.Now()
-	a.store.UpdateJob(ctx, job)
+// activeDeadline 任务的最长运行时间
+func activeDeadline(job *model.Job) time.Duration {
+	return time.Duration(job.Spec.ActiveDeadlineSeconds) * time.Second
+}
 
-	// 4. 上传日志 (不管成功失败，只要有日志就上传)
-	if output != "" {
-		err := a.store.SaveJobLog(ctx, job.ID, output)
-		if err != nil {
-			log.Printf("Failed to save job log: %v", err)
-		} else {
-			log.Printf("📝 Logs saved to Etcd for job %s", job.ID)
+// describeFailure 生成失败原因 (model.Reason*) 和详细信息，返回空字符串表示任务成功
+// lastLine 是任务输出的最后一个非空行，通常就是报错信息
+func describeFailure(result *executor.Result, err error, lastLine string) (reason, msg string) {
+	switch {
+	case errors.Is(err, errDeadlineExceeded):
+		return model.ReasonDeadlineExceeded, err.Error()
+	case err != nil:
+		return model.ReasonError, err.Error()
+	case result.OOMKilled:
+		return model.ReasonOOMKilled, fmt.Sprintf("OOM killed (exit code %d): exceeded memory limit", result.ExitCode)
+	case result.ExitCode != 0:
+		msg := fmt.Sprintf("exited with code %d", result.ExitCode)
+		if lastLine != "" {
+			msg += ": " + truncate(lastLine, maxErrorLineLen)
 		}
+		return model.ReasonError, msg
 	}
+	return "", ""
 }
 
-func (a *Agent) register(ctx context.Context) {
-	// 简单上报节点信息
-	node := &model.Node{
-		ID:      a.ID,
-		IP:      "127.0.0.1",
-		Version: "v1.0",
-		Status:  model.NodeReady,
-		// 这里恢复成真实的资源 (或者你之前修改过的 Mock 数据)
-		TotalCap: model.Resource{
-			MilliCPU: 4000,
-			Memory:   1024 * 1024 * 1024 * 8,
-		},
-		LastHeartbeat: time.Now().Unix(),
+// truncate 截断过长的文本 (按字符而不是字节)
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n]) + "..."
+	}
+	return s
+}
+
+// trackRunning 登记正在执行的任务，任务已经登记过时返回 false
+func (a *Agent) trackRunning(job *model.Job, cancel context.CancelFunc) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.running[job.ID]; ok {
+		return false
+	}
+	a.running[job.ID] = &runningJob{res: job.ResReq, attempt: job.Status.Retries, version: job.ResourceVersion, cancel: cancel}
+	return true
+}
+
+// markFinishing 任务已经执行完，接下来上报结果
+func (a *Agent) markFinishing(jobID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if rj, ok := a.running[jobID]; ok {
+		rj.finishing = true
+	}
+}
+
+func (a *Agent) untrackRunning(jobID string) {
+	a.mu.Lock()
+	delete(a.running, jobID)
+	a.mu.Unlock()
+}
+
+// attachLogs 记录任务的输出收集器，供 GetJobStream 订阅
+func (a *Agent) attachLogs(jobID string, logs *logCollector) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if rj, ok := a.running[jobID]; ok {
+		rj.logs = logs
+	}
+}
+
+// runningLogs 正在本节点执行的任务的输出收集器，任务不在本节点执行或还没开始时返回 nil
+func (a *Agent) runningLogs(jobID string) *logCollector {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if rj, ok := a.running[jobID]; ok {
+		return rj.logs
+	}
+	return nil
+}
+
+// stopJob 停止本节点上正在执行的任务，任务不在本节点上执行时返回 false
+func (a *Agent) stopJob(jobID, why string) bool {
+	a.mu.Lock()
+	rj, ok := a.running[jobID]
+	a.mu.Unlock()
+	if ok {
+		log.Printf("[Worker] 🛑 Stopping job %s (%s)", jobID, why)
+		rj.cancel()
+	}
+	return ok
+}
+
+// stopIfReassigned 任务已经不归本节点的这次执行时停止它：
+// 分配给了其他节点、执行次数变了 (已经被重试)，或者状态不再是 Scheduled / Running
+func (a *Agent) stopIfReassigned(job *model.Job) {
+	a.mu.Lock()
+	rj, ok := a.running[job.ID]
+	lost := ok && !rj.finishing && job.ResourceVersion > rj.version &&
+		(job.Status.NodeID != a.ID || job.Status.Retries != rj.attempt ||
+			(job.Status.State != model.JobScheduled && job.Status.State != model.JobRunning))
+	a.mu.Unlock()
+	if lost {
+		a.stopJob(job.ID, fmt.Sprintf("reassigned: state %s, node %q, attempt %d", job.Status.State, job.Status.NodeID, job.Status.Retries))
+	}
+}
+
+// allocated 汇总本节点正在执行的任务的资源占用
+func (a *Agent) allocated() model.Resource {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var total model.Resource
+	for _, rj := range a.running {
+		total.MilliCPU += rj.res.MilliCPU
+		total.Memory += rj.res.Memory
 	}
-	a.store.RegisterNode(ctx, node)
+	return total
 }
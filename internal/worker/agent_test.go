@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+func assignedJob(id string, version int64, state model.JobState, nodeID string, attempt int) *model.Job {
+	job := &model.Job{ID: id, ResourceVersion: version}
+	job.Status.State = state
+	job.Status.NodeID = nodeID
+	job.Status.Retries = attempt
+	return job
+}
+
+// trackForTest 登记一个正在执行的任务 (不真正执行)，返回它的 ctx
+func trackForTest(t *testing.T, a *Agent, job *model.Job) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if !a.trackRunning(job, cancel) {
+		t.Fatalf("job %s is already tracked", job.ID)
+	}
+	return ctx
+}
+
+func TestAgentStopIfReassigned(t *testing.T) {
+	tests := []struct {
+		name      string
+		finishing bool
+		event     *model.Job
+		wantStop  bool
+	}{
+		{"still running here", false, assignedJob("job", 6, model.JobRunning, "node-1", 0), false},
+		{"older event", false, assignedJob("job", 4, model.JobPending, "", 0), false},
+		{"rescued back to pending", false, assignedJob("job", 7, model.JobPending, "", 1), true},
+		{"rescued and failed", false, assignedJob("job", 7, model.JobFailed, "node-1", 0), true},
+		{"scheduled to another node", false, assignedJob("job", 9, model.JobScheduled, "node-2", 1), true},
+		{"newer attempt on this node", false, assignedJob("job", 9, model.JobScheduled, "node-1", 1), true},
+		{"own result being reported", true, assignedJob("job", 7, model.JobPending, "", 1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Agent{ID: "node-1", running: make(map[string]*runningJob)}
+			ctx := trackForTest(t, a, assignedJob("job", 5, model.JobScheduled, "node-1", 0))
+			if tt.finishing {
+				a.markFinishing("job")
+			}
+
+			a.stopIfReassigned(tt.event)
+			if stopped := ctx.Err() != nil; stopped != tt.wantStop {
+				t.Errorf("stopped = %v, want %v", stopped, tt.wantStop)
+			}
+		})
+	}
+}
+
+func TestAgentClaimStopsLostJobs(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	a := &Agent{ID: "node-1", store: s, running: make(map[string]*runningJob)}
+
+	for _, job := range []*model.Job{
+		assignedJob("kept", 0, model.JobRunning, "node-1", 0),
+		assignedJob("rescued", 0, model.JobPending, "", 1),
+		assignedJob("retried", 0, model.JobRunning, "node-1", 1),
+	} {
+		if err := s.CreateJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, _ := s.ListJobs(ctx, nil)
+
+	running := map[string]context.Context{
+		"kept":    trackForTest(t, a, assignedJob("kept", 1, model.JobScheduled, "node-1", 0)),
+		"rescued": trackForTest(t, a, assignedJob("rescued", 1, model.JobScheduled, "node-1", 0)),
+		"retried": trackForTest(t, a, assignedJob("retried", 1, model.JobScheduled, "node-1", 0)),
+		"deleted": trackForTest(t, a, assignedJob("deleted", 1, model.JobScheduled, "node-1", 0)),
+		// List 之后才推送过来的任务，不在 List 的结果里
+		"pushed": trackForTest(t, a, assignedJob("pushed", list.Revision+1, model.JobScheduled, "node-1", 0)),
+	}
+
+	if _, err := a.claimScheduledJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"kept": false, "rescued": true, "retried": true, "deleted": true, "pushed": false}
+	for jobID, runCtx := range running {
+		if stopped := runCtx.Err() != nil; stopped != want[jobID] {
+			t.Errorf("job %s stopped = %v, want %v", jobID, stopped, want[jobID])
+		}
+	}
+}
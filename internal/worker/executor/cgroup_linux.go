@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"titan/pkg/model"
+)
+
+const (
+	// cgroupFSRoot cgroup v2 的统一挂载点
+	cgroupFSRoot = "/sys/fs/cgroup"
+
+	// cpuPeriodUsec cpu.max 的调度周期 (100ms)，配额 = MilliCPU / 1000 * 周期
+	cpuPeriodUsec = 100000
+)
+
+// cgroupManager 在 root 下为每个任务创建一个 cgroup v2 子组
+type cgroupManager struct {
+	root string
+}
+
+// newCgroupManager 检查 cgroup v2 是否可用，并在 root 上开启 cpu / memory 控制器
+func newCgroupManager(root string) (*cgroupManager, error) {
+	if _, err := os.Stat(filepath.Join(cgroupFSRoot, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("cgroup v2 not mounted at %s: %w", cgroupFSRoot, err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create cgroup %s: %w", root, err)
+	}
+
+	// 子组要有 cpu.max / memory.max，父组必须先把控制器下放 (subtree_control)
+	for dir := filepath.Dir(root); strings.HasPrefix(dir, cgroupFSRoot); dir = filepath.Dir(dir) {
+		if err := enableControllers(dir); err != nil {
+			return nil, err
+		}
+		if dir == cgroupFSRoot {
+			break
+		}
+	}
+	if err := enableControllers(root); err != nil {
+		return nil, err
+	}
+	return &cgroupManager{root: root}, nil
+}
+
+func enableControllers(dir string) error {
+	if err := writeCgroupFile(dir, "cgroup.subtree_control", "+cpu +memory"); err != nil {
+		return fmt.Errorf("enable cpu/memory controllers in %s: %w", dir, err)
+	}
+	return nil
+}
+
+// jobCgroup 单个任务的 cgroup
+type jobCgroup struct {
+	path string
+	dir  *os.File // 用于 clone 时直接把子进程放进 cgroup (CLONE_INTO_CGROUP)
+}
+
+// create 为任务创建 cgroup，并按 ResReq 写入 cpu.max / memory.max
+func (m *cgroupManager) create(job *model.Job) (*jobCgroup, error) {
+	path := filepath.Join(m.root, job.ID)
+	if err := os.Mkdir(path, 0o755); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+
+	cpuMax := "max"
+	if job.ResReq.MilliCPU > 0 {
+		cpuMax = strconv.FormatInt(job.ResReq.MilliCPU*cpuPeriodUsec/1000, 10)
+	}
+	if err := writeCgroupFile(path, "cpu.max", fmt.Sprintf("%s %d", cpuMax, cpuPeriodUsec)); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	if job.ResReq.Memory > 0 {
+		if err := writeCgroupFile(path, "memory.max", strconv.FormatInt(job.ResReq.Memory, 10)); err != nil {
+			os.Remove(path)
+			return nil, err
+		}
+		// 不允许用 Swap 绕过内存限制 (没有开启 Swap 的机器上这个文件可能不存在)
+		writeCgroupFile(path, "memory.swap.max", "0")
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return &jobCgroup{path: path, dir: dir}, nil
+}
+
+// attach 让 cmd 启动的进程直接诞生在这个 cgroup 里 (没有"先启动再迁移"的时间窗口)
+// 取消时仍由进程组负责优雅退出，主进程结束后 Run 再 kill 整个 cgroup，
+// 连 setsid 逃出进程组的子进程也不放过
+func (g *jobCgroup) attach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(g.dir.Fd())
+}
+
+// kill 杀掉 cgroup 里的所有进程
+func (g *jobCgroup) kill() error {
+	// cgroup.kill 需要 5.14+ 内核，老内核退化为逐个 kill
+	if err := writeCgroupFile(g.path, "cgroup.kill", "1"); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(g.path, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(line); err == nil {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+	return nil
+}
+
+// usage 读取任务的资源使用统计
+func (g *jobCgroup) usage() (peakMemory int64, cpuTime time.Duration, oomKilled bool) {
+	// memory.peak 需要 5.19+ 内核，读不到就保持 0
+	if data, err := os.ReadFile(filepath.Join(g.path, "memory.peak")); err == nil {
+		peakMemory, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	}
+	if usec, ok := readKeyedValue(filepath.Join(g.path, "cpu.stat"), "usage_usec"); ok {
+		cpuTime = time.Duration(usec) * time.Microsecond
+	}
+	if kills, ok := readKeyedValue(filepath.Join(g.path, "memory.events"), "oom_kill"); ok {
+		oomKilled = kills > 0
+	}
+	return peakMemory, cpuTime, oomKilled
+}
+
+// destroy 清理 cgroup (进程全部退出后才能 rmdir)
+func (g *jobCgroup) destroy() {
+	g.dir.Close()
+	for i := 0; i < 10; i++ {
+		err := os.Remove(g.path)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		if !errors.Is(err, syscall.EBUSY) {
+			break
+		}
+		g.kill()
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0o644)
+}
+
+// readKeyedValue 读取 "key value" 格式文件 (cpu.stat / memory.events) 中的某一项
+func readKeyedValue(path, key string) (int64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			v, err := strconv.ParseInt(fields[1], 10, 64)
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+package executor
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// 非 Linux 平台没有 cgroup v2，ProcessExecutor 在这里退化为不做资源隔离
+type cgroupManager struct{}
+
+type jobCgroup struct{}
+
+func newCgroupManager(root string) (*cgroupManager, error) {
+	return nil, errors.New("cgroup v2 isolation is only supported on Linux")
+}
+
+func (m *cgroupManager) create(job *model.Job) (*jobCgroup, error) {
+	return nil, errors.New("cgroup v2 isolation is only supported on Linux")
+}
+
+func (g *jobCgroup) attach(cmd *exec.Cmd) {}
+
+func (g *jobCgroup) kill() error { return nil }
+
+func (g *jobCgroup) usage() (peakMemory int64, cpuTime time.Duration, oomKilled bool) {
+	return 0, 0, false
+}
+
+func (g *jobCgroup) destroy() {}
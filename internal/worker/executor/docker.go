@@ -1,9 +1,12 @@
 package executor
 
 import (
-	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"log"
+	"time"
+
 	"titan/pkg/model"
 
 	"github.com/docker/docker/api/types"
@@ -14,86 +17,159 @@ import (
 
 type DockerExecutor struct {
 	cli *client.Client
+
+	// 私有仓库凭据 (config.json 里的 auths、credsStore、credHelpers)
+	registryCreds *registryCredentials
 }
 
-// Init 初始化 Docker 客户端
+// dockerPingTimeout 启动时探测 Docker Daemon 是否可用的超时时间
+const dockerPingTimeout = 3 * time.Second
+
+// NewDockerExecutor 初始化 Docker 客户端，并确认 Docker Daemon 可用
 func NewDockerExecutor() (*DockerExecutor, error) {
 	// 自动从环境变量或默认路径连接本地 Docker
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion("1.44"))
 	if err != nil {
 		return nil, err
 	}
-	return &DockerExecutor{cli: cli}, nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), dockerPingTimeout)
+	defer cancel()
+	if _, err := cli.Ping(ctx); err != nil {
+		cli.Close()
+		return nil, fmt.Errorf("docker daemon unavailable: %w", err)
+	}
+	return &DockerExecutor{
+		cli:           cli,
+		registryCreds: loadRegistryCredentials(),
+	}, nil
 }
 
 // Run 真正执行任务的方法
-func (e *DockerExecutor) Run(ctx context.Context, job *model.Job) (string, error) {
+func (e *DockerExecutor) Run(ctx context.Context, job *model.Job, stdout, stderr io.Writer) (*Result, error) {
 	log.Printf("🐳 [Docker] Starting job %s...", job.ID)
 
 	// 1. 拉取镜像 (Pull Image)
-	// 为了演示快一点，如果本地有镜像可以注释掉这步，或者写个判断
-	imageName := "alpine:latest" // 默认用 alpine，体积小
-	//if job.Spec.Image != "" {
-	//	imageName = job.Spec.Image
-	//}
-
-	//log.Printf("   -> Pulling image: %s", imageName)
-	//reader, err := e.cli.ImagePull(ctx, imageName, types.ImagePullOptions{})
-	//if err != nil {
-	//	return "", err
-	//}
-	//io.Copy(os.Stdout, reader) // 把拉取进度打印出来
-	//reader.Close()
+	// 按 Spec.ImagePullPolicy 决定是否拉取，默认本地有就不拉 (IfNotPresent)
+	imageName, err := resolveImage(job)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.ensureImage(ctx, imageName, job.Spec.ImagePullPolicy); err != nil {
+		return nil, err
+	}
 
 	// 2. 创建容器 (Create Container)
+	// 资源限制和调度器的 ResReq 保持一致：申请多少就只能用多少
 	resp, err := e.cli.ContainerCreate(ctx, &container.Config{
 		Image: imageName,
 		Cmd:   job.Spec.Command, // 例如 ["echo", "hello"]
+		Env:   job.Spec.Envs,    // 例如 ["FOO=bar"]
 		Tty:   false,
-	}, nil, nil, nil, "")
+	}, hostConfigFor(job), nil, nil, "")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	containerID := resp.ID
 	log.Printf("   -> Container created: %s", containerID[:12])
 
+	// 6. 清理容器 (Remove) - 就像 defer 垃圾回收，任何出错路径都不会泄漏容器
+	defer e.cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true})
+
 	// 3. 启动容器 (Start Container)
 	if err := e.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{}); err != nil {
-		return "", err
+		return nil, err
 	}
 	log.Printf("   -> Container started, running...")
 
-	// 4. 等待容器结束 (Wait)
+	// 4. 实时转发日志 (Logs) - 这是给用户看的
+	// Follow 模式的日志流在容器停止时结束；任务被取消时也要把停止前的输出读完，所以不跟随 ctx 取消
+	logsDone := make(chan error, 1)
+	go func() {
+		logsDone <- e.streamLogs(context.WithoutCancel(ctx), containerID, stdout, stderr)
+	}()
+
+	// 5. 等待容器结束 (Wait)
+	// 任务被取消时 Wait 随 ctx 返回，先优雅停止容器，后面的步骤改用不会被取消的 Context，
+	// 这样已经产生的日志仍然可以取回
+	result := &Result{}
+	var stopErr error
 	statusCh, errCh := e.cli.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
 	select {
 	case err := <-errCh:
-		if err != nil {
-			return "", err
+		if ctx.Err() == nil {
+			return nil, err
 		}
-	case <-statusCh:
+		stopErr = fmt.Errorf("container stopped: %w", ctx.Err())
+		ctx = context.WithoutCancel(ctx)
+		e.stopContainer(ctx, containerID)
+	case status := <-statusCh:
+		if status.Error != nil {
+			return nil, fmt.Errorf("wait container: %s", status.Error.Message)
+		}
+		result.ExitCode = int(status.StatusCode)
+	}
+
+	if err := <-logsDone; err != nil {
+		log.Printf("   -> Failed to stream logs of container %s: %v", containerID[:12], err)
 	}
 
-	// 5. 获取日志 (Logs) - 这是给用户看的
-	outReader, err := e.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	// 7. 检查是否因为超出内存限制被内核 OOM Kill
+	inspect, err := e.cli.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return "", err
+		return result, err
+	}
+	if inspect.State != nil && inspect.State.OOMKilled {
+		log.Printf("💥 [Docker] Job %s was OOM killed", job.ID)
+		result.OOMKilled = true
 	}
-	defer outReader.Close()
 
-	// 使用 Buffer 捕获输出
-	var buf bytes.Buffer
-	// stdcopy 会把 docker 的多路复用流拆分，写入 buf
-	// 这里的 output 不再直接打印到 os.Stdout，而是存进内存
-	_, err = stdcopy.StdCopy(&buf, &buf, outReader)
+	if stopErr != nil {
+		log.Printf("🛑 [Docker] Job %s stopped: %v", job.ID, stopErr)
+		return result, stopErr
+	}
+	log.Printf("🏁 [Docker] Job %s exited with code %d", job.ID, result.ExitCode)
+
+	return result, nil
+}
+
+// streamLogs 跟随容器的日志流，直到容器停止
+// stdcopy 会把 docker 的多路复用流拆分成 stdout / stderr
+func (e *DockerExecutor) streamLogs(ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+	reader, err := e.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
 	if err != nil {
-		return "", err
+		return err
 	}
+	defer reader.Close()
 
-	log.Printf("✅ [Docker] Job %s finished successfully!", job.ID)
+	_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	return err
+}
 
-	// 6. 清理容器 (Remove) - 就像 defer 垃圾回收
-	e.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{})
+// stopContainer 先发 SIGTERM，stopGracePeriod 之后还没退出再由 Docker 强制杀掉
+func (e *DockerExecutor) stopContainer(ctx context.Context, containerID string) {
+	timeout := int(stopGracePeriod.Seconds())
+	if err := e.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
+		log.Printf("   -> Failed to stop container %s: %v", containerID[:12], err)
+	}
+}
 
-	return buf.String(), nil
+// hostConfigFor 把任务的资源申请 (model.Resource) 翻译成 Docker 的 cgroup 限制
+//   - MilliCPU -> NanoCPUs (1000m = 1 核 = 1e9 NanoCPUs)
+//   - Memory   -> Memory (字节)，Swap 上限和内存相同，即不允许额外使用 Swap
+func hostConfigFor(job *model.Job) *container.HostConfig {
+	hostConfig := &container.HostConfig{}
+	if job.ResReq.MilliCPU > 0 {
+		hostConfig.Resources.NanoCPUs = job.ResReq.MilliCPU * 1e6
+	}
+	if job.ResReq.Memory > 0 {
+		hostConfig.Resources.Memory = job.ResReq.Memory
+		hostConfig.Resources.MemorySwap = job.ResReq.Memory
+	}
+	return hostConfig
 }
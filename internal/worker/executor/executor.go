@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// Executor 任务执行器
+// Agent 根据 Job.Type 选择具体实现：SHELL -> ProcessExecutor，DOCKER -> DockerExecutor
+type Executor interface {
+	// Run 同步执行任务直到结束
+	// 只有"任务没能跑起来" (镜像拉取失败、命令不存在等) 才通过 error 返回
+	// 输出在运行过程中实时写入 stdout/stderr (两者可能被并发调用)
+	// ctx 被取消时停止任务 (留出 stopGracePeriod 优雅退出)，返回 ctx 的错误
+	Run(ctx context.Context, job *model.Job, stdout, stderr io.Writer) (*Result, error)
+}
+
+// stopGracePeriod 任务被取消/超时后，先发 SIGTERM，再等多久强制杀掉
+const stopGracePeriod = 10 * time.Second
+
+// 编译期检查
+var (
+	_ Executor = (*DockerExecutor)(nil)
+	_ Executor = (*ProcessExecutor)(nil)
+)
+
+// Result 一次任务执行的结果
+// 任务自身失败 (非零退出码、OOM) 体现在 Result 里
+type Result struct {
+	ExitCode  int  // 进程退出码
+	OOMKilled bool // 是否因超出内存限制被内核杀掉
+
+	// 资源用量 (执行器无法统计时为 0)
+	PeakMemory int64         // 内存峰值 (字节)
+	CPUTime    time.Duration // 累计 CPU 时间
+}
@@ -0,0 +1,276 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"titan/pkg/model"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// defaultShellImage Shell 任务没有指定镜像时使用的镜像
+const defaultShellImage = "alpine:latest"
+
+// dockerHubAuthKey Docker Hub 在 config.json 里的 Key
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// resolveImage 决定任务使用的镜像
+func resolveImage(job *model.Job) (string, error) {
+	if job.Spec.Image != "" {
+		return job.Spec.Image, nil
+	}
+	if job.Type == model.JobTypeDocker {
+		return "", fmt.Errorf("docker job %s has no image (spec.image is required)", job.ID)
+	}
+	return defaultShellImage, nil
+}
+
+// ensureImage 按拉取策略准备镜像，镜像无法获得时返回明确的错误
+func (e *DockerExecutor) ensureImage(ctx context.Context, imageName string, policy model.PullPolicy) error {
+	switch policy {
+	case model.PullAlways:
+		return e.pullImage(ctx, imageName)
+
+	case model.PullNever:
+		present, err := e.imagePresent(ctx, imageName)
+		if err != nil {
+			return err
+		}
+		if !present {
+			return fmt.Errorf("image %s not present on node and pull policy is %s", imageName, model.PullNever)
+		}
+		return nil
+
+	case model.PullIfNotPresent, "":
+		present, err := e.imagePresent(ctx, imageName)
+		if err != nil {
+			return err
+		}
+		if present {
+			return nil
+		}
+		return e.pullImage(ctx, imageName)
+
+	default:
+		return fmt.Errorf("unknown image pull policy %q", policy)
+	}
+}
+
+func (e *DockerExecutor) imagePresent(ctx context.Context, imageName string) (bool, error) {
+	_, _, err := e.cli.ImageInspectWithRaw(ctx, imageName)
+	if err == nil {
+		return true, nil
+	}
+	if client.IsErrNotFound(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("inspect image %s: %w", imageName, err)
+}
+
+// pullImage 拉取镜像 (私有仓库自动带上凭据，包括 credential helper 提供的凭据)
+func (e *DockerExecutor) pullImage(ctx context.Context, imageName string) error {
+	log.Printf("   -> Pulling image: %s", imageName)
+
+	opts := types.ImagePullOptions{}
+	auth, ok, err := e.registryCreds.lookup(ctx, imageName)
+	if err != nil {
+		return fmt.Errorf("pull image %s: %w", imageName, err)
+	}
+	if ok {
+		encoded, err := registry.EncodeAuthConfig(auth)
+		if err != nil {
+			return fmt.Errorf("encode registry credentials for %s: %w", imageName, err)
+		}
+		opts.RegistryAuth = encoded
+	}
+
+	reader, err := e.cli.ImagePull(ctx, imageName, opts)
+	if err != nil {
+		return fmt.Errorf("pull image %s: %w", imageName, err)
+	}
+	defer reader.Close()
+
+	// 拉取进度是 JSON 流，必须读完才算拉取结束；流里的错误 (比如鉴权失败) 也在这里返回
+	if err := jsonmessage.DisplayJSONMessagesStream(reader, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("pull image %s: %w", imageName, err)
+	}
+	return nil
+}
+
+// registryCredentials 私有仓库凭据，和 Docker CLI 一样按顺序查找：
+// credHelpers 里为该仓库指定的 helper -> credsStore -> auths 里的静态凭据
+type registryCredentials struct {
+	auths       map[string]registry.AuthConfig // 仓库域名 -> 凭据
+	credsStore  string                         // 默认的 credential helper (如 desktop、pass)
+	credHelpers map[string]string              // 仓库域名 -> credential helper
+}
+
+// credentialHelperTimeout 调用一次 docker-credential-<helper> 的超时时间
+const credentialHelperTimeout = 10 * time.Second
+
+// lookup 根据镜像所在的仓库查找凭据，没有凭据时返回 false (匿名拉取)
+// 配置了 credential helper 但无法调用时返回错误，而不是悄悄地匿名拉取
+func (c *registryCredentials) lookup(ctx context.Context, imageName string) (registry.AuthConfig, bool, error) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return registry.AuthConfig{}, false, nil
+	}
+
+	domain := reference.Domain(named)
+	if domain == "docker.io" {
+		domain = dockerHubAuthKey
+	}
+
+	helper, ok := c.credHelpers[domain]
+	if !ok {
+		helper = c.credsStore
+	}
+	if helper != "" {
+		return getFromCredentialHelper(ctx, helper, domain)
+	}
+
+	auth, ok := c.auths[domain]
+	return auth, ok, nil
+}
+
+// credentialHelperOutput docker-credential-<helper> get 的输出
+type credentialHelperOutput struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// credentialsNotFound helper 没有该仓库的凭据时输出的消息 (docker-credential-helpers 约定)
+const credentialsNotFound = "credentials not found in native keychain"
+
+// getFromCredentialHelper 执行 docker-credential-<helper> get，仓库地址从 stdin 传入
+func getFromCredentialHelper(ctx context.Context, helper, server string) (registry.AuthConfig, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialHelperTimeout)
+	defer cancel()
+
+	program := "docker-credential-" + helper
+	cmd := exec.CommandContext(ctx, program, "get")
+	cmd.Stdin = strings.NewReader(server)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return registry.AuthConfig{}, false, fmt.Errorf(
+				"registry %s uses credential helper %q but %s is not installed on this node", server, helper, program)
+		}
+		msg := strings.TrimSpace(stdout.String())
+		if msg == credentialsNotFound {
+			return registry.AuthConfig{}, false, nil
+		}
+		if msg == "" {
+			msg = strings.TrimSpace(stderr.String())
+		}
+		return registry.AuthConfig{}, false, fmt.Errorf("%s get %s: %w: %s", program, server, err, msg)
+	}
+
+	var out credentialHelperOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return registry.AuthConfig{}, false, fmt.Errorf("%s get %s: malformed output: %w", program, server, err)
+	}
+	auth := registry.AuthConfig{ServerAddress: server}
+	// Username 为 <token> 表示 Secret 是 identity token (如 Azure ACR)
+	if out.Username == "<token>" {
+		auth.IdentityToken = out.Secret
+	} else {
+		auth.Username, auth.Password = out.Username, out.Secret
+	}
+	return auth, true, nil
+}
+
+// dockerConfigFile Docker CLI 的配置文件格式 (~/.docker/config.json)，只关心凭据相关的部分
+type dockerConfigFile struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"` // base64(username:password)
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// registryKey config.json 里的 Key 可能带协议和路径 (如 https://registry.example.com/v1/)，统一成域名
+func registryKey(server string) string {
+	if server == dockerHubAuthKey {
+		return server
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	key, _, _ = strings.Cut(key, "/")
+	if key == "docker.io" || key == "index.docker.io" {
+		return dockerHubAuthKey
+	}
+	return key
+}
+
+// loadRegistryCredentials 读取私有仓库凭据
+// 复用 Docker CLI 的配置文件 ($DOCKER_CONFIG/config.json 或 ~/.docker/config.json)，
+// 在 Worker 上执行过 docker login 即可，凭据不会出现在任务定义 (Etcd) 里
+func loadRegistryCredentials() *registryCredentials {
+	creds := &registryCredentials{
+		auths:       make(map[string]registry.AuthConfig),
+		credHelpers: make(map[string]string),
+	}
+
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return creds
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return creds
+	}
+
+	var cfg dockerConfigFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Printf("[Docker] Ignoring malformed docker config: %v", err)
+		return creds
+	}
+
+	for server, entry := range cfg.Auths {
+		auth := registry.AuthConfig{
+			Username:      entry.Username,
+			Password:      entry.Password,
+			IdentityToken: entry.IdentityToken,
+			ServerAddress: server,
+		}
+		if entry.Auth != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil {
+				auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+			}
+		}
+		creds.auths[registryKey(server)] = auth
+	}
+	creds.credsStore = cfg.CredsStore
+	for server, helper := range cfg.CredHelpers {
+		creds.credHelpers[registryKey(server)] = helper
+	}
+	return creds
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+// fakeCredentialHelper 在 PATH 里放一个 docker-credential-<name>，按 stdin 里的仓库地址返回凭据
+func fakeCredentialHelper(t *testing.T, name string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper script needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+read server
+case "$server" in
+  registry.example.com) echo '{"ServerURL":"registry.example.com","Username":"alice","Secret":"s3cret"}' ;;
+  token.example.com) echo '{"ServerURL":"token.example.com","Username":"<token>","Secret":"id-token"}' ;;
+  broken.example.com) echo 'keychain locked' >&2; exit 1 ;;
+  *) echo 'credentials not found in native keychain'; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-"+name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLoadRegistryCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	config := `{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNz"},
+			"https://static.example.com/v1/": {"username": "bob", "password": "pw"}
+		},
+		"credsStore": "fake",
+		"credHelpers": {"gcr.io": "gcloud"}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds := loadRegistryCredentials()
+	if auth := creds.auths[dockerHubAuthKey]; auth.Username != "user" || auth.Password != "pass" {
+		t.Errorf("docker hub auth = %+v, want user/pass", auth)
+	}
+	if auth := creds.auths["static.example.com"]; auth.Username != "bob" {
+		t.Errorf("static.example.com auth = %+v, want bob", auth)
+	}
+	if creds.credsStore != "fake" || creds.credHelpers["gcr.io"] != "gcloud" {
+		t.Errorf("credsStore %q credHelpers %v, want fake and gcr.io -> gcloud", creds.credsStore, creds.credHelpers)
+	}
+}
+
+func TestRegistryCredentialsLookup(t *testing.T) {
+	fakeCredentialHelper(t, "fake")
+
+	tests := []struct {
+		name      string
+		creds     *registryCredentials
+		image     string
+		wantOK    bool
+		wantUser  string
+		wantToken string
+		wantErr   string
+	}{
+		{
+			name:     "static auths",
+			creds:    &registryCredentials{auths: map[string]registry.AuthConfig{"static.example.com": {Username: "bob"}}},
+			image:    "static.example.com/app:1",
+			wantOK:   true,
+			wantUser: "bob",
+		},
+		{
+			name:   "anonymous",
+			creds:  &registryCredentials{},
+			image:  "alpine:latest",
+			wantOK: false,
+		},
+		{
+			name:     "credsStore",
+			creds:    &registryCredentials{credsStore: "fake"},
+			image:    "registry.example.com/team/app",
+			wantOK:   true,
+			wantUser: "alice",
+		},
+		{
+			name:      "identity token",
+			creds:     &registryCredentials{credsStore: "fake"},
+			image:     "token.example.com/app",
+			wantOK:    true,
+			wantToken: "id-token",
+		},
+		{
+			name:     "credHelpers wins over credsStore",
+			creds:    &registryCredentials{credsStore: "missing", credHelpers: map[string]string{"registry.example.com": "fake"}},
+			image:    "registry.example.com/app",
+			wantOK:   true,
+			wantUser: "alice",
+		},
+		{
+			name:   "helper has no credentials",
+			creds:  &registryCredentials{credsStore: "fake"},
+			image:  "other.example.com/app",
+			wantOK: false,
+		},
+		{
+			name:    "helper fails",
+			creds:   &registryCredentials{credsStore: "fake"},
+			image:   "broken.example.com/app",
+			wantErr: "keychain locked",
+		},
+		{
+			name:    "helper not installed",
+			creds:   &registryCredentials{credsStore: "missing"},
+			image:   "registry.example.com/app",
+			wantErr: "docker-credential-missing is not installed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, ok, err := tt.creds.lookup(context.Background(), tt.image)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("lookup() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOK || auth.Username != tt.wantUser || auth.IdentityToken != tt.wantToken {
+				t.Errorf("lookup() = %+v, %v, want user %q token %q ok %v", auth, ok, tt.wantUser, tt.wantToken, tt.wantOK)
+			}
+		})
+	}
+}
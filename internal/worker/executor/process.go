@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// processWaitDelay 优雅退出时间过后，最多再等多久让输出管道关闭
+const processWaitDelay = 5 * time.Second
+
+// DefaultCgroupRoot Worker 为任务创建 cgroup 的默认父目录
+const DefaultCgroupRoot = "/sys/fs/cgroup/titan"
+
+// ProcessExecutor 直接在宿主机上以子进程运行 Shell 任务，不依赖 Docker
+//   - 每个任务一个独立的工作目录 (默认在 BaseDir 下按任务 ID 创建，结束后删除)
+//   - 环境变量只包含 PATH/HOME 和 Spec.Envs，不继承 Worker 自己的环境
+//   - 任务及其派生的子进程放在同一个进程组里，取消/超时时整组停止
+//   - 开启 cgroup v2 (EnableCgroups) 后按 ResReq 限制 CPU / 内存，并统计实际用量
+type ProcessExecutor struct {
+	// BaseDir 任务工作目录的根目录
+	BaseDir string
+
+	// cgroups 为 nil 表示不做资源隔离 (非 Linux，或没有 cgroup v2 / 权限不足)
+	cgroups *cgroupManager
+}
+
+// NewProcessExecutor 初始化进程执行器
+// 最长运行时间 (Spec.ActiveDeadlineSeconds) 由调用方通过 ctx 控制
+func NewProcessExecutor(baseDir string) (*ProcessExecutor, error) {
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &ProcessExecutor{BaseDir: baseDir}, nil
+}
+
+// EnableCgroups 在 root 下为每个任务创建 cgroup v2 子组，强制执行 ResReq
+// 失败时 (不是 Linux、没有挂载 cgroup v2、不是 root) 执行器保持不隔离的模式
+func (e *ProcessExecutor) EnableCgroups(root string) error {
+	m, err := newCgroupManager(root)
+	if err != nil {
+		return err
+	}
+	e.cgroups = m
+	return nil
+}
+
+// Run 执行 Spec.Command，输出实时写入 stdout/stderr
+func (e *ProcessExecutor) Run(ctx context.Context, job *model.Job, stdout, stderr io.Writer) (*Result, error) {
+	if len(job.Spec.Command) == 0 {
+		return nil, fmt.Errorf("shell job %s has no command", job.ID)
+	}
+	log.Printf("🖥️  [Process] Starting job %s...", job.ID)
+
+	// 1. 准备工作目录
+	workDir, cleanup, err := e.prepareWorkDir(job)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// 2. 构造命令
+	cmd := exec.CommandContext(ctx, job.Spec.Command[0], job.Spec.Command[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = append([]string{"PATH=" + os.Getenv("PATH"), "HOME=" + workDir}, job.Spec.Envs...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = stopGracePeriod + processWaitDelay
+	releaseGroup := setProcessGroup(cmd)
+
+	// 3. 放进任务自己的 cgroup (资源限制 + 取消时整组杀掉)
+	var cg *jobCgroup
+	if e.cgroups != nil {
+		cg, err = e.cgroups.create(job)
+		if err != nil {
+			return nil, fmt.Errorf("setup cgroup: %w", err)
+		}
+		defer cg.destroy()
+		cg.attach(cmd)
+	}
+
+	// 4. 运行并等待结束
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start command: %w", err)
+	}
+	log.Printf("   -> Process started, pid %d", cmd.Process.Pid)
+
+	err = cmd.Wait()
+	releaseGroup()
+	result := &Result{}
+	if cg != nil {
+		// 主进程退出后，留在 cgroup 里的后台子进程一并清理，再读取用量
+		cg.kill()
+		result.PeakMemory, result.CPUTime, result.OOMKilled = cg.usage()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return result, err
+	}
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("process killed: %w", ctx.Err())
+	}
+
+	log.Printf("🏁 [Process] Job %s exited with code %d", job.ID, result.ExitCode)
+	return result, nil
+}
+
+// prepareWorkDir 使用 Spec.WorkDir，或者为任务创建一个临时目录 (返回的 cleanup 负责删除)
+func (e *ProcessExecutor) prepareWorkDir(job *model.Job) (string, func(), error) {
+	if job.Spec.WorkDir != "" {
+		return job.Spec.WorkDir, func() {}, nil
+	}
+
+	dir := filepath.Join(e.BaseDir, job.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("create work dir: %w", err)
+	}
+	return dir, func() { os.RemoveAll(dir) }, nil
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package executor
+
+import "os/exec"
+
+// setProcessGroup 非 Unix 平台没有进程组，取消时只杀掉任务进程本身
+func setProcessGroup(cmd *exec.Cmd) (release func()) {
+	return func() {}
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package executor
+
+import (
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup 让任务进程成为新进程组的组长，取消时连同它派生的子进程一起处理：
+// 先给整组发 SIGTERM，stopGracePeriod 之后还没退出的整组 SIGKILL
+// cmd.Wait 返回后必须调用返回的 release：组长已经被回收，进程组 ID 可能被别的进程复用，不能再发信号
+func setProcessGroup(cmd *exec.Cmd) (release func()) {
+	var (
+		mu     sync.Mutex
+		timer  *time.Timer
+		exited bool
+	)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		mu.Lock()
+		timer = time.AfterFunc(stopGracePeriod, func() {
+			// WaitDelay 比 stopGracePeriod 长，到这里组长通常还没被回收
+			mu.Lock()
+			defer mu.Unlock()
+			if !exited {
+				syscall.Kill(-pgid, syscall.SIGKILL)
+			}
+		})
+		mu.Unlock()
+		return syscall.Kill(-pgid, syscall.SIGTERM)
+	}
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		exited = true
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"titan/pkg/logstore"
+	"titan/pkg/model"
+)
+
+const (
+	// maxLogChunkBytes 单个日志块的大小上限 (远小于 Etcd 单个 value 1.5MB 的限制)
+	maxLogChunkBytes = 64 * 1024
+
+	// maxLogLineBytes 单行的长度上限，超长的行 (比如没有换行的进度条) 拆成多行
+	maxLogLineBytes = 16 * 1024
+
+	// logFlushInterval 输出不多时，最多攒这么久就写一次，运行中的任务也能及时看到日志
+	logFlushInterval = 1 * time.Second
+
+	// logFlushTimeout 单次写入日志存储的超时时间
+	logFlushTimeout = 5 * time.Second
+
+	// 写入日志存储失败时的重试策略，重试完仍然失败才丢弃这一块
+	maxLogFlushAttempts   = 3
+	logFlushRetryInterval = 1 * time.Second
+
+	// maxPendingChunks 等待写入日志存储的块数上限，日志存储跟不上时任务的输出会被阻塞 (而不是无限占用内存)
+	maxPendingChunks = 16
+
+	// logSubscriberBuffer 实时输出订阅者的缓冲行数，跟不上的订阅者会被断开 (不能拖慢任务本身)
+	logSubscriberBuffer = 1024
+)
+
+// logCollector 收集一个任务的输出
+// 按行切分并打上来源 (stdout/stderr) 和时间戳，按大小或时间攒成 LogChunk 边运行边写入日志存储
+// 写入日志存储在单独的 goroutine 里按顺序进行，不持有 mu：日志存储慢的时候不会卡住任务的输出和实时订阅者，
+// 除非积压的块超过 maxPendingChunks
+type logCollector struct {
+	ctx     context.Context
+	logs    logstore.LogStore
+	jobID   string
+	attempt int
+
+	mu       sync.Mutex
+	seq      int64
+	lines    []model.LogLine
+	size     int
+	pending  []*model.LogChunk // 已经切好、还没写入日志存储的块 (按 seq 顺序，写完才移除)
+	partial  map[string][]byte // 各个流中还没遇到换行符的部分
+	lastLine string            // 最后一个非空行 (失败时作为错误信息)
+	closed   bool
+	drained  *sync.Cond // pending 变短时通知被阻塞的写入方
+
+	subscribers map[chan streamLine]struct{} // 实时输出的订阅者 (GetJobStream)
+
+	chunks int // 成功写入的块数 (只由 flushLoop 修改)
+
+	kick chan struct{} // 有新的块等待写入
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newLogCollector 开始收集任务输出
+// ctx 被取消后日志仍然要写完 (比如任务被取消时保留已有的输出)，所以这里不继承取消信号
+func newLogCollector(ctx context.Context, logs logstore.LogStore, job *model.Job) *logCollector {
+	c := &logCollector{
+		ctx:         context.WithoutCancel(ctx),
+		logs:        logs,
+		jobID:       job.ID,
+		attempt:     job.Status.Retries,
+		partial:     make(map[string][]byte),
+		subscribers: make(map[chan streamLine]struct{}),
+		kick:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	c.drained = sync.NewCond(&c.mu)
+	go c.flushLoop()
+	return c
+}
+
+// Writer 返回写入某个流 (model.StreamStdout / model.StreamStderr) 的 io.Writer，可以并发使用
+func (c *logCollector) Writer(stream string) io.Writer {
+	return &streamWriter{c: c, stream: stream}
+}
+
+type streamWriter struct {
+	c      *logCollector
+	stream string
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.c.write(w.stream, p)
+	return len(p), nil
+}
+
+// Close 写入剩余的输出 (包括没有换行符结尾的最后一行)，等待所有块写入日志存储后返回最后一个非空行
+func (c *logCollector) Close() string {
+	c.mu.Lock()
+	for _, stream := range []string{model.StreamStdout, model.StreamStderr} {
+		if rest := c.partial[stream]; len(rest) > 0 {
+			c.addLineLocked(stream, rest)
+		}
+	}
+	delete(c.partial, model.StreamStdout)
+	delete(c.partial, model.StreamStderr)
+	c.cutLocked()
+
+	c.closed = true
+	for ch := range c.subscribers {
+		close(ch)
+	}
+	c.subscribers = nil
+	c.drained.Broadcast()
+	c.mu.Unlock()
+
+	close(c.stop)
+	<-c.done
+
+	if c.chunks > 0 {
+		log.Printf("📝 Saved %d log chunk(s) for job %s", c.chunks, c.jobID)
+	}
+	return c.lastLine
+}
+
+func (c *logCollector) write(stream string, p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buf := append(c.partial[stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		c.addLineLocked(stream, buf[:i])
+		buf = buf[i+1:]
+	}
+	// 正好 maxLogLineBytes 的行不用切 (buf[cut] 会越界)，等换行符或 Close
+	for len(buf) > maxLogLineBytes {
+		// 在字符边界上切开，不把一个 UTF-8 字符拆成两半
+		cut := maxLogLineBytes
+		for cut > 0 && !utf8.RuneStart(buf[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxLogLineBytes // 不是合法的 UTF-8，直接按字节切
+		}
+		c.addLineLocked(stream, buf[:cut])
+		buf = buf[cut:]
+	}
+	c.partial[stream] = append([]byte(nil), buf...)
+
+	// 日志存储跟不上：等积压的块写出去再返回 (Wait 期间释放 mu，订阅者和另一个流不受影响)
+	for len(c.pending) >= maxPendingChunks && !c.closed {
+		c.drained.Wait()
+	}
+}
+
+func (c *logCollector) addLineLocked(stream string, line []byte) {
+	text := strings.TrimSuffix(string(line), "\r")
+	logLine := model.LogLine{Time: time.Now(), Stream: stream, Text: text}
+	c.publishLocked(streamLine{line: logLine, seq: c.seq, index: len(c.lines)})
+	c.lines = append(c.lines, logLine)
+	c.size += len(text) + len(stream) + 48 // 粗略估算 JSON 编码后的大小
+	if strings.TrimSpace(text) != "" {
+		c.lastLine = text
+	}
+	if c.size >= maxLogChunkBytes {
+		c.cutLocked()
+	}
+}
+
+// cutLocked 把攒下的行切成一个块，交给 flushLoop 写入日志存储
+func (c *logCollector) cutLocked() {
+	if len(c.lines) == 0 {
+		return
+	}
+	c.pending = append(c.pending, &model.LogChunk{
+		JobID:   c.jobID,
+		Attempt: c.attempt,
+		Seq:     c.seq,
+		Lines:   c.lines,
+	})
+	c.seq++
+	c.lines = nil
+	c.size = 0
+
+	select {
+	case c.kick <- struct{}{}:
+	default:
+	}
+}
+
+// streamLine 推送给订阅者的一行输出，(seq, index) 是它在日志块中的位置
+type streamLine struct {
+	line  model.LogLine
+	seq   int64
+	index int
+}
+
+// subscribe 订阅任务的实时输出
+// 先回放还没写入日志存储的行 (等待写入的块和正在攒的行；更早的块已经可以从日志存储读到)，之后推送新的行
+// 任务结束时通道关闭；订阅者跟不上时也会被断开 (通道关闭)，调用方需要 unsubscribe
+func (c *logCollector) subscribe() (ch chan streamLine, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, false
+	}
+
+	buffered := len(c.lines)
+	for _, chunk := range c.pending {
+		buffered += len(chunk.Lines)
+	}
+	ch = make(chan streamLine, logSubscriberBuffer+buffered)
+	for _, chunk := range c.pending {
+		for i, line := range chunk.Lines {
+			ch <- streamLine{line: line, seq: chunk.Seq, index: i}
+		}
+	}
+	for i, line := range c.lines {
+		ch <- streamLine{line: line, seq: c.seq, index: i}
+	}
+	c.subscribers[ch] = struct{}{}
+	return ch, true
+}
+
+func (c *logCollector) unsubscribe(ch chan streamLine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.subscribers[ch]; ok {
+		delete(c.subscribers, ch)
+		close(ch)
+	}
+}
+
+// finished 任务输出是否已经全部写完 (用来区分订阅通道关闭的原因)
+func (c *logCollector) finished() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *logCollector) publishLocked(l streamLine) {
+	for ch := range c.subscribers {
+		select {
+		case ch <- l:
+		default:
+			delete(c.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// flushLoop 定时切块，并按顺序把块写入日志存储；Close 之后写完剩下的块再退出
+func (c *logCollector) flushLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			c.cutLocked()
+			c.mu.Unlock()
+		case <-c.kick:
+		case <-c.stop:
+			c.flushPending()
+			return
+		}
+		c.flushPending()
+	}
+}
+
+// flushPending 依次写入等待中的块 (不持有 mu)
+func (c *logCollector) flushPending() {
+	for {
+		c.mu.Lock()
+		if len(c.pending) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		chunk := c.pending[0]
+		c.mu.Unlock()
+
+		if c.save(chunk) {
+			c.chunks++
+		}
+
+		c.mu.Lock()
+		c.pending = c.pending[1:]
+		c.drained.Broadcast()
+		c.mu.Unlock()
+	}
+}
+
+// save 写入一个块，失败时重试，重试完仍然失败只记录日志并丢弃这一块 (不影响任务本身的执行)
+func (c *logCollector) save(chunk *model.LogChunk) bool {
+	var err error
+	for attempt := 1; attempt <= maxLogFlushAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(logFlushRetryInterval)
+		}
+		ctx, cancel := context.WithTimeout(c.ctx, logFlushTimeout)
+		err = c.logs.AppendJobLog(ctx, chunk)
+		cancel()
+		if err == nil {
+			return true
+		}
+	}
+	log.Printf("[Worker] Failed to save logs of job %s (chunk %d, %d lines dropped after %d attempts): %v",
+		c.jobID, chunk.Seq, len(chunk.Lines), maxLogFlushAttempts, err)
+	return false
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+func TestLogCollectorSplitsLongLines(t *testing.T) {
+	// "é" 占两个字节，放在第 maxLogLineBytes 个字节上，横跨切分位置
+	straddle := strings.Repeat("a", maxLogLineBytes-1) + "é" + "b"
+
+	tests := []struct {
+		name   string
+		output string
+		want   []int // 每行的字节数
+	}{
+		{"exactly the limit", strings.Repeat("a", maxLogLineBytes), []int{maxLogLineBytes}},
+		{"exactly the limit with newline", strings.Repeat("a", maxLogLineBytes) + "\n", []int{maxLogLineBytes}},
+		{"one byte over", strings.Repeat("a", maxLogLineBytes+1), []int{maxLogLineBytes, 1}},
+		{"rune across the limit", straddle, []int{maxLogLineBytes - 1, 3}},
+		{"two lines", strings.Repeat("a", 2*maxLogLineBytes) + "\nb\n", []int{maxLogLineBytes, maxLogLineBytes, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logs := store.NewMemoryStore()
+			job := &model.Job{ID: "job-1"}
+			c := newLogCollector(ctx, logs, job)
+
+			// 分两次写入，切分不能依赖单次 Write 的边界
+			w := c.Writer(model.StreamStdout)
+			half := len(tt.output) / 2
+			w.Write([]byte(tt.output[:half]))
+			w.Write([]byte(tt.output[half:]))
+			c.Close()
+
+			chunks, err := logs.GetJobLogs(ctx, job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			var joined strings.Builder
+			for _, chunk := range chunks {
+				for _, line := range chunk.Lines {
+					if !utf8.ValidString(line.Text) {
+						t.Errorf("line %q is not valid UTF-8", line.Text)
+					}
+					got = append(got, len(line.Text))
+					joined.WriteString(line.Text)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("line lengths = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("line lengths = %v, want %v", got, tt.want)
+				}
+			}
+			if want := strings.ReplaceAll(tt.output, "\n", ""); joined.String() != want {
+				t.Error("split lines do not add up to the original output")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/convert"
+	"titan/api/pb"
+	"titan/pkg/model"
+)
+
+const (
+	// rpcTimeout 单次调用 Master 的超时时间
+	rpcTimeout = 5 * time.Second
+
+	// 任务状态上报遇到 Master 暂时不可用时的重试策略 (结果丢了任务会一直停在 Running)
+	maxReportAttempts   = 5
+	reportRetryInterval = 2 * time.Second
+)
+
+// register 向 Master 注册本节点，成功返回 true
+func (a *Agent) register(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	_, err := a.master.RegisterNode(ctx, &pb.RegisterNodeRequest{
+		NodeId:        a.ID,
+		Ip:            a.IP,
+		Port:          int32(a.port),
+		Version:       "v1.0",
+		LogAddr:       a.logAddr,
+		TotalResource: convert.ResourceToPB(a.capacity),
+		JobTypes:      a.jobTypes(),
+	})
+	if err != nil {
+		log.Printf("[Worker] Failed to register with master: %v", err)
+		return false
+	}
+	return true
+}
+
+// jobTypes 本节点能执行的任务类型 (有对应的执行器)
+func (a *Agent) jobTypes() []string {
+	types := make([]string, 0, len(a.executors))
+	for t := range a.executors {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// heartbeat 上报心跳和剩余资源，Master 不认识本节点时返回 false (需要重新注册)
+// 剩余资源按本节点实际在跑的任务计算 (调度器以自己的账本为准，这里只用于观测)
+func (a *Agent) heartbeat(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+
+	allocated := a.allocated()
+	_, err := a.master.SendHeartbeat(ctx, &pb.HeartbeatRequest{
+		NodeId: a.ID,
+		AvailableResource: convert.ResourceToPB(model.Resource{
+			MilliCPU: a.capacity.MilliCPU - allocated.MilliCPU,
+			Memory:   a.capacity.Memory - allocated.Memory,
+		}),
+		Timestamp: time.Now().Unix(),
+	})
+	if status.Code(err) == codes.NotFound {
+		return false
+	}
+	if err != nil {
+		log.Printf("[Worker] Failed to send heartbeat: %v", err)
+	}
+	return true
+}
+
+// reportStatus 向 Master 上报任务状态 (自动填上任务 ID、本节点和第几次执行)
+// Master 暂时不可用时等待重试；任务已不归本节点 (被取消、改派) 时 Master 返回 FAILED_PRECONDITION
+func (a *Agent) reportStatus(ctx context.Context, job *model.Job, req *pb.UpdateJobStatusRequest) (*pb.UpdateJobStatusResponse, error) {
+	req.JobId = job.ID
+	req.NodeId = a.ID
+	req.Attempt = int32(job.Status.Retries)
+
+	for attempt := 1; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, rpcTimeout)
+		resp, err := a.master.UpdateJobStatus(callCtx, req)
+		cancel()
+
+		code := status.Code(err)
+		if err == nil || attempt >= maxReportAttempts || (code != codes.Unavailable && code != codes.DeadlineExceeded) {
+			return resp, err
+		}
+		log.Printf("[Worker] Master unavailable, retrying status report of job %s: %v", job.ID, err)
+		select {
+		case <-time.After(reportRetryInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"titan/api/pb"
+	"titan/internal/auth"
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// workerService 供 Master 调用：推送任务、停止任务、读取实时输出
+type workerService struct {
+	pb.UnimplementedWorkerServiceServer
+	agent *Agent
+
+	// Agent 的生命周期：推送过来的任务在请求返回之后继续执行，直到 Agent 退出
+	ctx context.Context
+}
+
+// serve 在 lis 上提供 WorkerService，直到 ctx 结束
+// 只接受带有节点 Token 的请求 (Master)，配置了证书时使用 TLS
+func (a *Agent) serve(ctx context.Context, lis net.Listener) {
+	opts, err := a.Auth.ServerOptions(auth.Tokens{pb.WorkerService_ServiceDesc.ServiceName: a.Auth.NodeToken})
+	if err != nil {
+		log.Printf("[Worker] WorkerService disabled: %v", err)
+		lis.Close()
+		return
+	}
+	srv := grpc.NewServer(opts...)
+	pb.RegisterWorkerServiceServer(srv, &workerService{agent: a, ctx: ctx})
+
+	go func() {
+		<-ctx.Done()
+		srv.Stop() // GetJobStream 可能一直不结束，直接断开
+	}()
+
+	log.Printf("[Worker] WorkerService listening on %s", lis.Addr())
+	if err := srv.Serve(lis); err != nil {
+		log.Printf("[Worker] WorkerService stopped: %v", err)
+	}
+}
+
+// StartJob 开始执行 Master 推送的任务
+// 推送只带任务 ID，执行的内容以集群状态中的任务为准，且必须是分配给本节点的 Scheduled 任务；
+// 之后仍然要先通过 Master 从 Scheduled 变为 Running，期间被取消的任务在那一步被拒绝
+func (w *workerService) StartJob(ctx context.Context, req *pb.StartJobRequest) (*pb.StartJobResponse, error) {
+	if req.JobId == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+	job, err := w.agent.store.GetJob(ctx, req.JobId)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", req.JobId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get job %s: %v", req.JobId, err)
+	}
+	if job.Status.NodeID != w.agent.ID || job.Status.State != model.JobScheduled {
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s is not scheduled to node %s (state: %s, node: %s)",
+			job.ID, w.agent.ID, job.Status.State, job.Status.NodeID)
+	}
+	if !w.agent.startJob(w.ctx, job) {
+		return &pb.StartJobResponse{Success: false}, nil
+	}
+	log.Printf("[Worker] ⚡ Received job: %s (pushed by master)", job.ID)
+	return &pb.StartJobResponse{Success: true}, nil
+}
+
+func (w *workerService) StopJob(ctx context.Context, req *pb.StopJobRequest) (*pb.StopJobResponse, error) {
+	return &pb.StopJobResponse{Success: w.agent.stopJob(req.JobId, "cancelled")}, nil
+}
+
+// GetJobStream 推送任务的实时输出，任务结束时正常关闭
+func (w *workerService) GetJobStream(req *pb.GetJobStreamRequest, stream grpc.ServerStreamingServer[pb.JobStreamResponse]) error {
+	logs := w.agent.runningLogs(req.JobId)
+	if logs == nil {
+		return status.Errorf(codes.NotFound, "job %s is not running on node %s", req.JobId, w.agent.ID)
+	}
+	lines, ok := logs.subscribe()
+	if !ok {
+		return status.Errorf(codes.NotFound, "job %s is not running on node %s", req.JobId, w.agent.ID)
+	}
+	defer logs.unsubscribe(lines)
+
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				if !logs.finished() {
+					return status.Error(codes.ResourceExhausted, "client is too slow, stream dropped")
+				}
+				return nil
+			}
+			err := stream.Send(&pb.JobStreamResponse{
+				Output:       []byte(l.line.Text),
+				Stream:       l.line.Stream,
+				TimeUnixNano: l.line.Time.UnixNano(),
+				Attempt:      int32(logs.attempt),
+				Seq:          l.seq,
+				Index:        int32(l.index),
+			})
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package logstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// cleanupInterval 多久检查一次过期的本地日志
+const cleanupInterval = 1 * time.Hour
+
+// FileStore 把日志写在 Worker 本地磁盘上：<dir>/<jobID>/<attempt>.jsonl，每行一个 LogChunk
+// 通过 Serve 提供 HTTP 读取接口，Master 用 RemoteStore 读取
+type FileStore struct {
+	dir       string
+	addr      string
+	endpoint  string
+	token     string // 读取接口要求的 Bearer Token，为空时不校验
+	retention time.Duration
+
+	mu sync.Mutex // 串行化写入，保证同一文件中的块不会交错
+}
+
+var _ LogStore = (*FileStore)(nil)
+
+// NewFileStore 创建本地日志存储
+// addr 是读取接口的监听地址；advertise 是其他节点访问它的地址，为空时由 addr 推断；
+// token 非空时读取接口只接受带有 "Authorization: Bearer <token>" 的请求
+func NewFileStore(dir, addr, advertise, token string, retention time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+	if advertise == "" {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log addr %q: %w", addr, err)
+		}
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "127.0.0.1"
+		}
+		advertise = "http://" + net.JoinHostPort(host, port)
+	}
+	return &FileStore{
+		dir:       dir,
+		addr:      addr,
+		endpoint:  strings.TrimSuffix(advertise, "/"),
+		token:     token,
+		retention: retention,
+	}, nil
+}
+
+// Endpoint 读取接口对外的地址 (Worker 注册时写入 Node.LogAddr)
+func (f *FileStore) Endpoint() string {
+	return f.endpoint
+}
+
+func (f *FileStore) AppendJobLog(ctx context.Context, chunk *model.LogChunk) error {
+	jobDir, err := f.jobDir(chunk.JobID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.MkdirAll(jobDir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(attemptPath(jobDir, chunk.Attempt), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f *FileStore) GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error) {
+	chunks, _, err := f.readChunks(jobID, nil)
+	return chunks, err
+}
+
+func (f *FileStore) WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk {
+	end := func(ctx context.Context) (*cursor, error) {
+		return f.end(jobID)
+	}
+	return pollWatch(ctx, end, func(ctx context.Context, after *cursor) ([]*model.LogChunk, *cursor, error) {
+		return f.readChunks(jobID, after)
+	})
+}
+
+// chunkPage 读取接口的响应：after 之后的日志块，以及下次接着读的位置
+type chunkPage struct {
+	Chunks []*model.LogChunk `json:"chunks"`
+	Next   *cursor           `json:"next,omitempty"`
+}
+
+// Serve 运行日志读取接口，直到 ctx 结束；同时定期删除过期的日志
+//
+//	GET /logs/<jobID>?after_attempt=1&after_seq=20&after_offset=4096  返回位置之后的日志块 (chunkPage)
+//	GET /logs/<jobID>/end                                             最后一块的位置 (还没有日志时为 null)
+func (f *FileStore) Serve(ctx context.Context) error {
+	srv := &http.Server{Addr: f.addr, Handler: f.handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	go f.cleanupLoop(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("[Logs] Serving job logs from %s on %s", f.dir, f.addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (f *FileStore) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /logs/{jobID}", f.handleGetLogs)
+	mux.HandleFunc("GET /logs/{jobID}/end", f.handleGetEnd)
+	return f.authenticate(mux)
+}
+
+// authenticate 校验请求携带的 Token (日志里可能有敏感信息，不能绕过 Master 的认证直接读取)
+func (f *FileStore) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if f.token != "" && (!ok || subtle.ConstantTimeCompare([]byte(token), []byte(f.token)) != 1) {
+			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *FileStore) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	var after *cursor
+	if q := r.URL.Query(); q.Has("after_attempt") {
+		attempt, err1 := strconv.Atoi(q.Get("after_attempt"))
+		seq, err2 := strconv.ParseInt(q.Get("after_seq"), 10, 64)
+		var offset int64
+		var err3 error
+		if v := q.Get("after_offset"); v != "" {
+			offset, err3 = strconv.ParseInt(v, 10, 64)
+		}
+		if err := errors.Join(err1, err2, err3); err != nil {
+			http.Error(w, "invalid cursor: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		after = &cursor{Attempt: attempt, Seq: seq, Offset: offset}
+	}
+
+	chunks, next, err := f.readChunks(r.PathValue("jobID"), after)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chunkPage{Chunks: chunks, Next: next})
+}
+
+func (f *FileStore) handleGetEnd(w http.ResponseWriter, r *http.Request) {
+	end, err := f.end(r.PathValue("jobID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(end)
+}
+
+// readChunks 读取任务 after 之后的日志块，返回下次接着读的位置
+// after 带有 Offset 时直接从文件的这个位置往后读，不用重新解析之前的内容
+func (f *FileStore) readChunks(jobID string, after *cursor) ([]*model.LogChunk, *cursor, error) {
+	jobDir, err := f.jobDir(jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	from := 0
+	if after != nil {
+		from = after.Attempt
+	}
+	attempts, err := attemptFiles(jobDir, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chunks := make([]*model.LogChunk, 0)
+	next := after
+	for _, attempt := range attempts {
+		start, filter := int64(0), after
+		if after != nil && attempt == after.Attempt && after.Offset > 0 {
+			start, filter = after.Offset, nil // 这个位置之后都是新写入的
+		}
+		read, end, err := readChunkFile(attemptPath(jobDir, attempt), start, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		chunks = append(chunks, read...)
+		if end > start {
+			c := cursor{Attempt: attempt, Seq: -1, Offset: end}
+			if n := len(read); n > 0 {
+				c.Seq = read[n-1].Seq
+			} else if next != nil && next.Attempt == attempt {
+				c.Seq = next.Seq
+			}
+			next = &c
+		}
+	}
+	return chunks, next, nil
+}
+
+// end 最后一块的位置：最后一次执行的文件中最后一个完整行之后，还没有日志时返回 nil
+func (f *FileStore) end(jobID string) (*cursor, error) {
+	jobDir, err := f.jobDir(jobID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := attemptFiles(jobDir, 0)
+	if err != nil || len(attempts) == 0 {
+		return nil, err
+	}
+	attempt := attempts[len(attempts)-1]
+	offset, err := lastLineEnd(attemptPath(jobDir, attempt))
+	if err != nil {
+		return nil, err
+	}
+	// 还没有完整的块时 Offset 为 0，Seq 为 -1：这次执行的所有块都算新写入的
+	return &cursor{Attempt: attempt, Seq: -1, Offset: offset}, nil
+}
+
+// attemptFiles 任务目录中第 from 次及之后的执行 (有日志文件的)，从小到大排序
+func attemptFiles(jobDir string, from int) ([]int, error) {
+	entries, err := os.ReadDir(jobDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []int
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok {
+			continue
+		}
+		attempt, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		if attempt >= from {
+			attempts = append(attempts, attempt)
+		}
+	}
+	sort.Ints(attempts)
+	return attempts, nil
+}
+
+func attemptPath(jobDir string, attempt int) string {
+	return filepath.Join(jobDir, fmt.Sprintf("%06d.jsonl", attempt))
+}
+
+// readChunkFile 从 offset 开始读取一个 .jsonl 文件中 after 之后的块，返回读完的位置 (最后一个完整行之后)
+// 最后一行可能正在写入 (不完整)，这种行不算读完，下次读取时再读到
+func readChunkFile(path string, offset int64, after *cursor) ([]*model.LogChunk, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	var chunks []*model.LogChunk
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// io.EOF：读完了；没有换行结尾的部分是还没写完的块
+			return chunks, offset, nil
+		}
+		offset += int64(len(line))
+		var chunk model.LogChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			log.Printf("[Logs] Failed to unmarshal log chunk in %s: %v", path, err)
+			continue
+		}
+		if after == nil || after.before(&chunk) {
+			chunks = append(chunks, &chunk)
+		}
+	}
+}
+
+// lastLineEnd 文件中最后一个完整行之后的位置 (从文件末尾往前找换行符)
+func lastLineEnd(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 4096)
+	for end := info.Size(); end > 0; {
+		start := max(end-int64(len(buf)), 0)
+		n, err := file.ReadAt(buf[:end-start], start)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// jobDir 任务的日志目录；拒绝包含路径分隔符的 ID，防止读写到日志目录之外
+func (f *FileStore) jobDir(jobID string) (string, error) {
+	if jobID == "" || jobID == "." || jobID == ".." || strings.ContainsAny(jobID, `/\`) {
+		return "", fmt.Errorf("invalid job id %q", jobID)
+	}
+	return filepath.Join(f.dir, jobID), nil
+}
+
+// cleanupLoop 删除超过保留时间没有写入的任务日志目录
+func (f *FileStore) cleanupLoop(ctx context.Context) {
+	if f.retention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		f.cleanup(time.Now().Add(-f.retention))
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (f *FileStore) cleanup(cutoff time.Time) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		log.Printf("[Logs] Failed to scan log dir: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		jobDir := filepath.Join(f.dir, entry.Name())
+		if lastWrite(jobDir).After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(jobDir); err != nil {
+			log.Printf("[Logs] Failed to remove expired logs of job %s: %v", entry.Name(), err)
+			continue
+		}
+		log.Printf("[Logs] Removed expired logs of job %s", entry.Name())
+	}
+}
+
+// lastWrite 目录中最后一次写入的时间 (追加写只会更新文件的修改时间，不会更新目录的)
+func lastWrite(dir string) time.Time {
+	var latest time.Time
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
@@ -0,0 +1,175 @@
+package logstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+func newTestFileStore(t *testing.T, token string) *FileStore {
+	f, err := NewFileStore(t.TempDir(), "127.0.0.1:0", "", token, DefaultRetention)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	return f
+}
+
+func appendChunks(t *testing.T, ls LogStore, chunks ...*model.LogChunk) {
+	t.Helper()
+	for _, chunk := range chunks {
+		if err := ls.AppendJobLog(context.Background(), chunk); err != nil {
+			t.Fatalf("AppendJobLog(%d/%d) error = %v", chunk.Attempt, chunk.Seq, err)
+		}
+	}
+}
+
+func TestFileStoreReadChunksIncremental(t *testing.T) {
+	f := newTestFileStore(t, "")
+	appendChunks(t, f, testChunk("job-1", 0, 0, "a"), testChunk("job-1", 0, 1, "b"))
+
+	chunks, next, err := f.readChunks("job-1", nil)
+	if err != nil {
+		t.Fatalf("readChunks() error = %v", err)
+	}
+	if got := strings.Join(chunkTexts(chunks), ","); got != "a,b" {
+		t.Fatalf("readChunks() = %q, want %q", got, "a,b")
+	}
+	path := attemptPath(filepath.Join(f.dir, "job-1"), 0)
+	info, _ := os.Stat(path)
+	if next == nil || next.Attempt != 0 || next.Seq != 1 || next.Offset != info.Size() {
+		t.Fatalf("next = %+v, want {0 1 %d}", next, info.Size())
+	}
+
+	// 正在写入的半行不算读完，写完之后下一次读取才返回
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	file.WriteString(`{"job_id":"job-1","attempt":0,"seq":2,`)
+	if end, err := f.end("job-1"); err != nil || end.Offset != next.Offset {
+		t.Fatalf("end() = %+v, %v, want offset %d", end, err, next.Offset)
+	}
+	chunks, partial, err := f.readChunks("job-1", next)
+	if err != nil || len(chunks) != 0 || *partial != *next {
+		t.Fatalf("readChunks(partial line) = %v, %+v, %v, want nothing new", chunkTexts(chunks), partial, err)
+	}
+	file.WriteString(`"lines":[{"stream":"stdout","text":"c"}]}` + "\n")
+	appendChunks(t, f, testChunk("job-1", 1, 0, "retry"))
+
+	// 从上次的位置接着读：把已经读过的部分改坏也不影响结果
+	data, _ := os.ReadFile(path)
+	copy(data, "XXXX")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, next, err = f.readChunks("job-1", next)
+	if err != nil {
+		t.Fatalf("readChunks() error = %v", err)
+	}
+	if got := strings.Join(chunkTexts(chunks), ","); got != "c,retry" {
+		t.Fatalf("readChunks(next) = %q, want %q", got, "c,retry")
+	}
+	if next.Attempt != 1 || next.Seq != 0 {
+		t.Fatalf("next = %+v, want attempt 1 seq 0", next)
+	}
+}
+
+func TestFileStoreAuthenticate(t *testing.T) {
+	f := newTestFileStore(t, "node-token")
+	appendChunks(t, f, testChunk("job-1", 0, 0, "secret"))
+	srv := httptest.NewServer(f.handler())
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer other", http.StatusUnauthorized},
+		{"not bearer", "node-token", http.StatusUnauthorized},
+		{"node token", "Bearer node-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/logs/job-1", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := store.NewMemoryStore()
+
+	// 第 0 次在 node-a 上失败，第 1 次正在 node-b 上执行
+	stores := make(map[string]*FileStore)
+	for _, nodeID := range []string{"node-a", "node-b"} {
+		f := newTestFileStore(t, "node-token")
+		srv := httptest.NewServer(f.handler())
+		t.Cleanup(srv.Close)
+		stores[nodeID] = f
+		if err := s.RegisterNode(ctx, &model.Node{ID: nodeID, LogAddr: srv.URL}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job := &model.Job{ID: "job-1"}
+	job.Status.Retries = 1
+	job.Status.NodeID = "node-b"
+	job.Status.Attempts = []model.Attempt{{NodeID: "node-a"}}
+	if err := s.CreateJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	appendChunks(t, stores["node-a"], testChunk("job-1", 0, 0, "a0"), testChunk("job-1", 0, 1, "a1"))
+	appendChunks(t, stores["node-b"], testChunk("job-1", 1, 0, "b0"))
+
+	if _, err := NewRemoteStore(s, "wrong").GetJobLogs(ctx, "job-1"); err == nil {
+		t.Fatal("GetJobLogs() with a wrong token succeeded")
+	}
+
+	r := NewRemoteStore(s, "node-token")
+	chunks, err := r.GetJobLogs(ctx, "job-1")
+	if err != nil {
+		t.Fatalf("GetJobLogs() error = %v", err)
+	}
+	if got := strings.Join(chunkTexts(chunks), ","); got != "a0,a1,b0" {
+		t.Fatalf("GetJobLogs() = %q, want %q", got, "a0,a1,b0")
+	}
+
+	watch := r.WatchJobLogs(ctx, "job-1")
+	appendChunks(t, stores["node-b"], testChunk("job-1", 1, 1, "b1"), testChunk("job-1", 1, 2, "b2"))
+	var got []*model.LogChunk
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case chunk := <-watch:
+			got = append(got, chunk)
+		case <-timeout:
+			t.Fatalf("timed out waiting for chunks, got %v", chunkTexts(got))
+		}
+	}
+	if texts := strings.Join(chunkTexts(got), ","); texts != "b1,b2" {
+		t.Fatalf("watched %q, want %q", texts, "b1,b2")
+	}
+}
@@ -0,0 +1,160 @@
+// Package logstore 任务输出的存储
+// 日志和集群状态分开存放：Etcd 只适合少量日志，量大时可以放在 Worker 本地磁盘或 S3 兼容的对象存储里
+package logstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// LogStore 任务输出的读写接口
+type LogStore interface {
+	// AppendJobLog 追加一段任务输出 (Worker 在任务运行过程中按块写入)
+	AppendJobLog(ctx context.Context, chunk *model.LogChunk) error
+
+	// GetJobLogs 按写入顺序 (attempt, seq) 读取任务的全部输出 (还没有输出时返回空切片)
+	GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error)
+
+	// WatchJobLogs 监听任务新写入的日志块 (只推送调用之后写入的；轮询实现的后端读不到起点时会从头推送，调用方需要去重)
+	// 通道关闭表示 Watch 结束 (ctx 结束或连接中断)，调用方可以重新 GetJobLogs 补齐之后再 Watch
+	WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk
+}
+
+// Etcd 和内存 Store 自带日志存储 (Key 前缀 store.LogKeyPrefix)
+var (
+	_ LogStore = (*store.EtcdManager)(nil)
+	_ LogStore = (*store.MemoryStore)(nil)
+)
+
+// 日志后端 (Config.Backend)
+const (
+	BackendEtcd = "etcd" // 和集群状态存在一起，只适合少量日志 (默认)
+	BackendFS   = "fs"   // 存在 Worker 本地磁盘，通过 Worker 上的 HTTP 接口读取
+	BackendS3   = "s3"   // 存在 S3 兼容的对象存储 (AWS S3 / MinIO 等)
+)
+
+// 默认配置
+const (
+	DefaultLogAddr   = ":9091"
+	DefaultRetention = 72 * time.Hour
+	DefaultS3Prefix  = "titan/logs"
+)
+
+// Config 日志后端配置
+type Config struct {
+	Backend string
+
+	// fs 后端
+	Dir           string        // Worker 本地保存日志的目录
+	Addr          string        // Worker 上日志读取接口的监听地址
+	AdvertiseAddr string        // 其他节点访问读取接口用的地址 (如 http://10.0.0.5:9091)，为空时由 Addr 推断
+	Retention     time.Duration // 本地日志保留多久，过期的任务目录会被删除
+	Token         string        // 读取接口的 Bearer Token，由调用方设为节点 Token (auth.Config.NodeToken)
+
+	// s3 后端
+	S3 S3Config
+}
+
+// S3Config S3 兼容对象存储的连接配置
+type S3Config struct {
+	Endpoint  string // 如 s3.amazonaws.com、localhost:9000
+	Bucket    string // 需要事先创建；日志保留时间由 Bucket 的生命周期规则控制
+	Prefix    string // 对象 Key 的前缀
+	AccessKey string
+	SecretKey string
+	Region    string
+	Secure    bool // 是否使用 HTTPS
+}
+
+// ConfigFromEnv 从环境变量读取配置：
+//
+//	TITAN_LOG_BACKEND         etcd (默认) | fs | s3
+//	TITAN_LOG_DIR             fs: 本地目录 (默认 $TMPDIR/titan/logs)
+//	TITAN_LOG_ADDR            fs: 读取接口的监听地址 (默认 :9091)
+//	TITAN_LOG_ADVERTISE_ADDR  fs: 读取接口对外的地址
+//	TITAN_LOG_RETENTION       fs: 本地日志保留时间 (默认 72h)
+//	TITAN_S3_ENDPOINT / TITAN_S3_BUCKET / TITAN_S3_PREFIX / TITAN_S3_REGION
+//	TITAN_S3_ACCESS_KEY / TITAN_S3_SECRET_KEY / TITAN_S3_SECURE
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Backend:       envOr("TITAN_LOG_BACKEND", BackendEtcd),
+		Dir:           envOr("TITAN_LOG_DIR", filepath.Join(os.TempDir(), "titan", "logs")),
+		Addr:          envOr("TITAN_LOG_ADDR", DefaultLogAddr),
+		AdvertiseAddr: os.Getenv("TITAN_LOG_ADVERTISE_ADDR"),
+		Retention:     DefaultRetention,
+		S3: S3Config{
+			Endpoint:  os.Getenv("TITAN_S3_ENDPOINT"),
+			Bucket:    os.Getenv("TITAN_S3_BUCKET"),
+			Prefix:    envOr("TITAN_S3_PREFIX", DefaultS3Prefix),
+			AccessKey: os.Getenv("TITAN_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("TITAN_S3_SECRET_KEY"),
+			Region:    os.Getenv("TITAN_S3_REGION"),
+		},
+	}
+	if v := os.Getenv("TITAN_LOG_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid TITAN_LOG_RETENTION %q: %w", v, err)
+		}
+		cfg.Retention = d
+	}
+	if v := os.Getenv("TITAN_S3_SECURE"); v != "" {
+		secure, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid TITAN_S3_SECURE %q: %w", v, err)
+		}
+		cfg.S3.Secure = secure
+	}
+	return cfg, nil
+}
+
+// OpenWriter Worker 使用的日志存储 (写入本节点运行的任务的输出)
+// fs 后端返回 *FileStore，调用方需要运行它的 Serve 把日志提供给其他节点读取
+func OpenWriter(cfg Config, s store.Store) (LogStore, error) {
+	switch cfg.Backend {
+	case BackendFS:
+		return NewFileStore(cfg.Dir, cfg.Addr, cfg.AdvertiseAddr, cfg.Token, cfg.Retention)
+	default:
+		return open(cfg, s)
+	}
+}
+
+// OpenReader CLI / Master 使用的日志存储 (读取任意任务的输出)
+// fs 后端从任务运行过的节点上读取 (通过 Node.LogAddr 找到节点，以 Config.Token 认证)，不支持写入
+func OpenReader(cfg Config, s store.Store) (LogStore, error) {
+	switch cfg.Backend {
+	case BackendFS:
+		return NewRemoteStore(s, cfg.Token), nil
+	default:
+		return open(cfg, s)
+	}
+}
+
+func open(cfg Config, s store.Store) (LogStore, error) {
+	switch cfg.Backend {
+	case BackendEtcd, "":
+		ls, ok := s.(LogStore)
+		if !ok {
+			return nil, fmt.Errorf("store %T does not support storing logs", s)
+		}
+		return ls, nil
+	case BackendS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown log backend %q (want %s, %s or %s)", cfg.Backend, BackendEtcd, BackendFS, BackendS3)
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
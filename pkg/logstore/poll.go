@@ -0,0 +1,89 @@
+package logstore
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// pollInterval 不支持推送的后端 (fs / s3) 靠轮询实现 WatchJobLogs
+const pollInterval = 1 * time.Second
+
+// cursor 日志块的位置，按 (attempt, seq) 排序
+// Offset 只用于 fs 后端：这一块在 <attempt>.jsonl 中结束的位置 (字节)，下次从这里接着读，不用重读整个文件；
+// 为 0 表示未知，读取时按 seq 过滤
+type cursor struct {
+	Attempt int   `json:"attempt"`
+	Seq     int64 `json:"seq"`
+	Offset  int64 `json:"offset,omitempty"`
+}
+
+func cursorOf(chunk *model.LogChunk) cursor {
+	return cursor{Attempt: chunk.Attempt, Seq: chunk.Seq}
+}
+
+// before c 是否排在 chunk 之前 (即 chunk 是 c 之后写入的)
+func (c cursor) before(chunk *model.LogChunk) bool {
+	if chunk.Attempt != c.Attempt {
+		return chunk.Attempt > c.Attempt
+	}
+	return chunk.Seq > c.Seq
+}
+
+// endFunc 当前最后一块的位置，还没有日志时返回 nil
+type endFunc func(ctx context.Context) (*cursor, error)
+
+// fetchFunc 读取 after 之后的日志块 (after 为 nil 表示全部)，按 (attempt, seq) 排好序，
+// 同时返回读完之后的位置 (没有新的块时返回 after)
+type fetchFunc func(ctx context.Context, after *cursor) ([]*model.LogChunk, *cursor, error)
+
+// pollWatch 用轮询实现 WatchJobLogs：先记下当前最后一块的位置，之后每隔 pollInterval 只读取新写入的块
+// 读取出错时下一轮再试 (比如节点暂时连不上)，ctx 结束时才关闭通道
+func pollWatch(ctx context.Context, end endFunc, fetch fetchFunc) <-chan *model.LogChunk {
+	chunkChan := make(chan *model.LogChunk)
+
+	// 同步确定起点，保证调用返回之后写入的块都会被推送
+	// 起点读不到时从头推送 (宁可重复也不能漏)，调用方按 (attempt, seq) 去重
+	after, err := end(ctx)
+	if err != nil {
+		after = nil
+	}
+
+	go func() {
+		defer close(chunkChan)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			chunks, next, err := fetch(ctx, after)
+			if err != nil {
+				continue
+			}
+			for _, chunk := range chunks {
+				select {
+				case chunkChan <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+			after = next
+		}
+	}()
+
+	return chunkChan
+}
+
+// sortChunks 按 (attempt, seq) 排序
+func sortChunks(chunks []*model.LogChunk) {
+	sort.Slice(chunks, func(i, j int) bool {
+		return cursorOf(chunks[i]).before(chunks[j])
+	})
+}
@@ -0,0 +1,61 @@
+package logstore
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// TestPollWatchKeepsPollingWhenUnreachable 节点暂时连不上 (end / fetch 出错) 时 Watch 不能结束，
+// 否则调用方会立刻重新 Watch，一直原地打转；恢复之后要推送 (包括起点之前的) 日志块
+func TestPollWatchKeepsPollingWhenUnreachable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errUnreachable := errors.New("node unreachable")
+	var reachable atomic.Bool
+	var ends atomic.Int64
+	end := func(ctx context.Context) (*cursor, error) {
+		ends.Add(1)
+		return nil, errUnreachable
+	}
+	fetch := func(ctx context.Context, after *cursor) ([]*model.LogChunk, *cursor, error) {
+		if !reachable.Load() {
+			return nil, after, errUnreachable
+		}
+		chunk := &model.LogChunk{JobID: "job-1", Seq: 0}
+		if after != nil && !after.before(chunk) {
+			return nil, after, nil
+		}
+		next := cursorOf(chunk)
+		return []*model.LogChunk{chunk}, &next, nil
+	}
+
+	ch := pollWatch(ctx, end, fetch)
+	select {
+	case _, ok := <-ch:
+		t.Fatalf("pollWatch() delivered before the node came back (ok = %v)", ok)
+	case <-time.After(pollInterval + pollInterval/2):
+	}
+
+	reachable.Store(true)
+	select {
+	case chunk, ok := <-ch:
+		if !ok || chunk.Seq != 0 {
+			t.Fatalf("pollWatch() = %v, %v, want chunk 0", chunk, ok)
+		}
+	case <-time.After(3 * pollInterval):
+		t.Fatal("pollWatch() did not deliver after the node came back")
+	}
+	if n := ends.Load(); n != 1 {
+		t.Errorf("end() called %d times, want 1", n)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
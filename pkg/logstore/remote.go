@@ -0,0 +1,216 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"titan/pkg/model"
+	"titan/pkg/store"
+)
+
+// remoteFetchTimeout 从单个节点读取日志的超时时间
+const remoteFetchTimeout = 10 * time.Second
+
+// errReadOnly fs 后端只有运行任务的 Worker 自己能写
+var errReadOnly = errors.New("fs log backend: logs can only be written by the worker running the job")
+
+// RemoteStore 读取保存在 Worker 本地磁盘上的日志 (fs 后端)
+// 从任务的执行记录中找到运行过它的节点，再通过节点的 LogAddr 读取
+// 节点下线后，它上面的日志就读不到了
+type RemoteStore struct {
+	store  store.Store
+	token  string // 读取接口要求的 Bearer Token (节点 Token)
+	client *http.Client
+}
+
+var _ LogStore = (*RemoteStore)(nil)
+
+func NewRemoteStore(s store.Store, token string) *RemoteStore {
+	return &RemoteStore{
+		store:  s,
+		token:  token,
+		client: &http.Client{Timeout: remoteFetchTimeout},
+	}
+}
+
+func (r *RemoteStore) AppendJobLog(ctx context.Context, chunk *model.LogChunk) error {
+	return errReadOnly
+}
+
+// GetJobLogs 部分节点读取失败时返回其余节点上的日志，并打印警告；全部失败才返回错误
+func (r *RemoteStore) GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error) {
+	chunks, _, err := r.fetch(ctx, jobID, nil)
+	if err != nil {
+		if len(chunks) == 0 {
+			return nil, err
+		}
+		log.Printf("[Logs] ⚠️ Some logs of job %s are unavailable: %v", jobID, err)
+	}
+	return chunks, nil
+}
+
+func (r *RemoteStore) WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk {
+	end := func(ctx context.Context) (*cursor, error) {
+		return r.end(ctx, jobID)
+	}
+	return pollWatch(ctx, end, func(ctx context.Context, after *cursor) ([]*model.LogChunk, *cursor, error) {
+		chunks, next, err := r.fetch(ctx, jobID, after)
+		if len(chunks) > 0 {
+			// 能读到的先推送，读不到的节点下一轮再试
+			return chunks, next, nil
+		}
+		return chunks, next, err
+	})
+}
+
+// end 最后一块的位置：从最近一次执行往前找第一个有日志的节点
+func (r *RemoteStore) end(ctx context.Context, jobID string) (*cursor, error) {
+	nodeIDs, logAddrs, err := r.attemptNodes(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := len(nodeIDs) - 1; attempt >= 0; attempt-- {
+		nodeID := nodeIDs[attempt]
+		if nodeID == "" {
+			continue
+		}
+		addr := logAddrs[nodeID]
+		if addr == "" {
+			return nil, fmt.Errorf("node %s is offline or does not serve logs", nodeID)
+		}
+		var end *cursor
+		if err := r.get(ctx, addr+"/logs/"+url.PathEscape(jobID)+"/end", &end); err != nil {
+			return nil, fmt.Errorf("node %s: %w", nodeID, err)
+		}
+		if end != nil {
+			return end, nil
+		}
+	}
+	return nil, nil
+}
+
+// fetch 从运行过这个任务的节点读取 after 之后的日志块，合并排序，同时返回读完之后的位置
+// 只访问执行过 after.Attempt 及之后各次的节点；某个节点读取失败时，
+// 结果截断到它负责的第一次执行之前，保证下一轮从这里重新读取而不会跳过它的日志
+func (r *RemoteStore) fetch(ctx context.Context, jobID string, after *cursor) ([]*model.LogChunk, *cursor, error) {
+	nodeIDs, logAddrs, err := r.attemptNodes(ctx, jobID)
+	if err != nil {
+		return nil, after, err
+	}
+	from := 0
+	if after != nil {
+		from = after.Attempt
+	}
+
+	// 同一个节点可能执行过多次，只需要读一次；firstAttempt 是它在 from 之后的第一次执行
+	var order []string
+	firstAttempt := make(map[string]int)
+	for attempt := from; attempt < len(nodeIDs); attempt++ {
+		nodeID := nodeIDs[attempt]
+		if _, ok := firstAttempt[nodeID]; nodeID != "" && !ok {
+			firstAttempt[nodeID] = attempt
+			order = append(order, nodeID)
+		}
+	}
+
+	chunks := make([]*model.LogChunk, 0)
+	next := after
+	failedAttempt := -1
+	var errs []error
+	for _, nodeID := range order {
+		addr := logAddrs[nodeID]
+		var page chunkPage
+		if addr == "" {
+			err = fmt.Errorf("node %s is offline or does not serve logs", nodeID)
+		} else {
+			err = r.get(ctx, addr+"/logs/"+url.PathEscape(jobID)+afterQuery(after), &page)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node %s: %w", nodeID, err))
+			if attempt := firstAttempt[nodeID]; failedAttempt < 0 || attempt < failedAttempt {
+				failedAttempt = attempt
+			}
+			continue
+		}
+		chunks = append(chunks, page.Chunks...)
+		if page.Next != nil && (next == nil || page.Next.Attempt > next.Attempt ||
+			(page.Next.Attempt == next.Attempt && page.Next.Seq >= next.Seq)) {
+			next = page.Next
+		}
+	}
+	sortChunks(chunks)
+
+	if failedAttempt >= 0 && after != nil {
+		n := sort.Search(len(chunks), func(i int) bool { return chunks[i].Attempt >= failedAttempt })
+		chunks, next = chunks[:n], after
+		if n > 0 {
+			// 不知道这一块在文件中的位置，下一轮按 seq 过滤
+			c := cursorOf(chunks[n-1])
+			next = &c
+		}
+	}
+	return chunks, next, errors.Join(errs...)
+}
+
+// attemptNodes 任务每次执行所在的节点 (下标是执行次数，还没开始的一次为空)，以及各节点的日志读取地址
+func (r *RemoteStore) attemptNodes(ctx context.Context, jobID string) ([]string, map[string]string, error) {
+	job, err := r.store.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := r.store.ListNodes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	logAddrs := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		logAddrs[node.ID] = node.LogAddr
+	}
+
+	// 每次执行结束都会记入 Status.Attempts，所以第 i 次执行就是 Attempts[i]，正在进行的是第 Retries 次
+	nodeIDs := make([]string, 0, len(job.Status.Attempts)+1)
+	for _, attempt := range job.Status.Attempts {
+		nodeIDs = append(nodeIDs, attempt.NodeID)
+	}
+	if job.Status.Retries == len(nodeIDs) && job.Status.NodeID != "" {
+		nodeIDs = append(nodeIDs, job.Status.NodeID)
+	}
+	return nodeIDs, logAddrs, nil
+}
+
+func afterQuery(after *cursor) string {
+	if after == nil {
+		return ""
+	}
+	return fmt.Sprintf("?after_attempt=%d&after_seq=%d&after_offset=%d", after.Attempt, after.Seq, after.Offset)
+}
+
+// get 带上 Token 请求节点的读取接口，把 JSON 响应解码到 v
+func (r *RemoteStore) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
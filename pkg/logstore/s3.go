@@ -0,0 +1,154 @@
+package logstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"titan/pkg/model"
+)
+
+// s3ConnectTimeout 启动时检查 Bucket 的超时时间
+const s3ConnectTimeout = 10 * time.Second
+
+// S3Store 把日志写到 S3 兼容的对象存储，每个日志块一个对象：
+// <prefix>/<jobID>/<attempt>/<seq>.json，数字补零，按 Key 排序就是写入顺序
+// 日志的保留时间由 Bucket 的生命周期规则 (Lifecycle) 控制
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+var _ LogStore = (*S3Store)(nil)
+
+// NewS3Store 连接对象存储，并确认 Bucket 已经存在
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 log backend requires an endpoint and a bucket")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.Secure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s3ConnectTimeout)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check bucket %s: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("bucket %s does not exist", cfg.Bucket)
+	}
+
+	return &S3Store{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *S3Store) AppendJobLog(ctx context.Context, chunk *model.LogChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, s.chunkKey(chunk.JobID, chunk.Attempt, chunk.Seq),
+		bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
+
+func (s *S3Store) GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error) {
+	return s.readChunks(ctx, jobID, nil)
+}
+
+func (s *S3Store) WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk {
+	end := func(ctx context.Context) (*cursor, error) {
+		return s.lastChunk(ctx, jobID)
+	}
+	return pollWatch(ctx, end, func(ctx context.Context, after *cursor) ([]*model.LogChunk, *cursor, error) {
+		chunks, err := s.readChunks(ctx, jobID, after)
+		if err != nil || len(chunks) == 0 {
+			return nil, after, err
+		}
+		next := cursorOf(chunks[len(chunks)-1])
+		return chunks, &next, nil
+	})
+}
+
+// lastChunk 最后一个日志块的位置 (只列出 Key，不读取内容)，还没有日志时返回 nil
+func (s *S3Store) lastChunk(ctx context.Context, jobID string) (*cursor, error) {
+	var last string
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.jobPrefix(jobID), Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		last = obj.Key
+	}
+	if last == "" {
+		return nil, nil
+	}
+	var c cursor
+	if _, err := fmt.Sscanf(strings.TrimPrefix(last, s.jobPrefix(jobID)), "%d/%d.json", &c.Attempt, &c.Seq); err != nil {
+		return nil, fmt.Errorf("unexpected log object %s: %w", last, err)
+	}
+	return &c, nil
+}
+
+// readChunks 按 Key 顺序列出 after 之后的对象并逐个读取
+func (s *S3Store) readChunks(ctx context.Context, jobID string, after *cursor) ([]*model.LogChunk, error) {
+	opts := minio.ListObjectsOptions{Prefix: s.jobPrefix(jobID), Recursive: true}
+	if after != nil {
+		opts.StartAfter = s.chunkKey(jobID, after.Attempt, after.Seq)
+	}
+
+	chunks := make([]*model.LogChunk, 0)
+	for obj := range s.client.ListObjects(ctx, s.bucket, opts) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		chunk, err := s.readChunk(ctx, obj.Key)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", obj.Key, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+func (s *S3Store) readChunk(ctx context.Context, key string) (*model.LogChunk, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, err
+	}
+	var chunk model.LogChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+// jobPrefix 某个任务所有日志块的公共前缀 (以 / 结尾，避免 job-1 匹配到 job-10 的日志)
+func (s *S3Store) jobPrefix(jobID string) string {
+	return path.Join(s.prefix, jobID) + "/"
+}
+
+func (s *S3Store) chunkKey(jobID string, attempt int, seq int64) string {
+	return fmt.Sprintf("%s%06d/%010d.json", s.jobPrefix(jobID), attempt, seq)
+}
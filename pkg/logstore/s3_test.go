@@ -0,0 +1,284 @@
+package logstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// fakeS3 只实现 S3Store 用到的几个接口的内存对象存储：
+// HEAD Bucket、PUT / GET Object、ListObjectsV2 (prefix + start-after)
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	lists   []string // 每次 ListObjectsV2 的 start-after
+	gets    int      // GET Object 的次数
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, S3Config) {
+	f := &fakeS3{bucket: bucket, objects: make(map[string][]byte)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, S3Config{
+		Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+		Bucket:    bucket,
+		Prefix:    "titan/logs",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Region:    "us-east-1", // 指定 Region，客户端就不会去查询 Bucket 的位置
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `<Error><Code>NoSuchBucket</Code><BucketName>%s</BucketName></Error>`, bucket)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+	case key == "" && r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r)
+	case r.Method == http.MethodPut:
+		data, err := readBody(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `<Error><Code>NoSuchKey</Code><Key>%s</Key></Error>`, key)
+			return
+		}
+		f.gets++
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Write(data)
+	default:
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix, startAfter := q.Get("prefix"), q.Get("start-after")
+	f.lists = append(f.lists, startAfter)
+
+	type content struct {
+		Key          string
+		Size         int
+		ETag         string
+		LastModified string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: f.bucket, Prefix: prefix, MaxKeys: 1000}
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && key > startAfter {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			Size:         len(f.objects[key]),
+			ETag:         `"etag"`,
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	result.KeyCount = len(keys)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+// readBody 读取 PutObject 的内容，客户端可能使用 aws-chunked 编码 (带签名或校验和的流式上传)
+func readBody(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var data []byte
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+		chunk := make([]byte, size+2) // 数据 + \r\n
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+func testChunk(jobID string, attempt int, seq int64, text string) *model.LogChunk {
+	return &model.LogChunk{
+		JobID:   jobID,
+		Attempt: attempt,
+		Seq:     seq,
+		Lines:   []model.LogLine{{Stream: model.StreamStdout, Text: text}},
+	}
+}
+
+func chunkTexts(chunks []*model.LogChunk) []string {
+	texts := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		for _, line := range chunk.Lines {
+			texts = append(texts, line.Text)
+		}
+	}
+	return texts
+}
+
+func TestNewS3StoreMissingBucket(t *testing.T) {
+	_, cfg := newFakeS3(t, "logs")
+	cfg.Bucket = "missing"
+	if _, err := NewS3Store(cfg); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("NewS3Store() error = %v, want bucket does not exist", err)
+	}
+}
+
+func TestS3StoreGetJobLogs(t *testing.T) {
+	fake, cfg := newFakeS3(t, "logs")
+	s, err := NewS3Store(cfg)
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// 乱序写入，另外一个前缀相同的任务 (job-10) 不能混进来
+	for _, chunk := range []*model.LogChunk{
+		testChunk("job-1", 1, 0, "c"),
+		testChunk("job-1", 0, 1, "b"),
+		testChunk("job-10", 0, 0, "other"),
+		testChunk("job-1", 0, 0, "a"),
+		testChunk("job-1", 0, 10, "b2"), // 补零后 10 排在 1 之后
+	} {
+		if err := s.AppendJobLog(ctx, chunk); err != nil {
+			t.Fatalf("AppendJobLog(%d/%d) error = %v", chunk.Attempt, chunk.Seq, err)
+		}
+	}
+	if _, ok := fake.objects["titan/logs/job-1/000000/0000000010.json"]; !ok {
+		t.Fatalf("unexpected object keys: %v", fake.objects)
+	}
+
+	tests := []struct {
+		jobID string
+		want  string
+	}{
+		{"job-1", "a,b,b2,c"},
+		{"job-10", "other"},
+		{"job-2", ""},
+	}
+	for _, tt := range tests {
+		chunks, err := s.GetJobLogs(ctx, tt.jobID)
+		if err != nil {
+			t.Fatalf("GetJobLogs(%s) error = %v", tt.jobID, err)
+		}
+		if got := strings.Join(chunkTexts(chunks), ","); got != tt.want {
+			t.Errorf("GetJobLogs(%s) = %q, want %q", tt.jobID, got, tt.want)
+		}
+	}
+}
+
+func TestS3StoreWatchJobLogs(t *testing.T) {
+	fake, cfg := newFakeS3(t, "logs")
+	s, err := NewS3Store(cfg)
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := s.AppendJobLog(ctx, testChunk("job-1", 0, 0, "old")); err != nil {
+		t.Fatal(err)
+	}
+	watch := s.WatchJobLogs(ctx, "job-1")
+
+	// Watch 开始之后写入的块按顺序推送，之前的不推送
+	for _, chunk := range []*model.LogChunk{
+		testChunk("job-1", 0, 1, "new-1"),
+		testChunk("job-1", 1, 0, "retry-0"),
+	} {
+		if err := s.AppendJobLog(ctx, chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []*model.LogChunk
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case chunk := <-watch:
+			got = append(got, chunk)
+		case <-timeout:
+			t.Fatalf("timed out waiting for chunks, got %v", chunkTexts(got))
+		}
+	}
+	if texts := strings.Join(chunkTexts(got), ","); texts != "new-1,retry-0" {
+		t.Fatalf("watched %q, want %q", texts, "new-1,retry-0")
+	}
+
+	// 之后的轮询从最后一块之后开始列出，不重新读取已经推送过的对象
+	if err := s.AppendJobLog(ctx, testChunk("job-1", 1, 1, "retry-1")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case chunk := <-watch:
+		if chunk.Attempt != 1 || chunk.Seq != 1 {
+			t.Fatalf("watched chunk %d/%d, want 1/1", chunk.Attempt, chunk.Seq)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for chunk 1/1")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.gets != 3 {
+		t.Errorf("read %d objects, want 3 (each new chunk once)", fake.gets)
+	}
+	for i, startAfter := range fake.lists[1:] { // 第一次是 Watch 开始时查找最后一块
+		if startAfter == "" {
+			t.Errorf("poll %d listed the whole job, want it to start after the last chunk", i+1)
+		}
+	}
+}
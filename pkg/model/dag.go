@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortByDependencies 校验一组任务 (一个工作流) 的依赖关系，并按拓扑序返回：被依赖的任务排在前面
+//   - 任务 ID 不能为空，也不能重复
+//   - 依赖的 ID 要么在这组任务里，要么 known 返回 true (之前已经提交过的任务)
+//   - 不允许出现环 (包括依赖自己)
+//
+// 校验不通过时返回 *ValidationError，字段路径指向出问题的任务，如 jobs[2].dependencies[0]
+func SortByDependencies(jobs []*Job, known func(id string) bool) ([]*Job, error) {
+	e := &fieldErrors{}
+	index := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		e.prefix = fmt.Sprintf("jobs[%d].", i)
+		if job.ID == "" {
+			e.add("id", "must not be empty")
+			continue
+		}
+		if first, dup := index[job.ID]; dup {
+			e.add("id", "duplicate id %q (also used by jobs[%d])", job.ID, first)
+			continue
+		}
+		index[job.ID] = i
+	}
+
+	for i, job := range jobs {
+		e.prefix = fmt.Sprintf("jobs[%d].", i)
+		for k, dep := range job.Dependencies {
+			if _, ok := index[dep]; !ok && !known(dep) {
+				e.add(fmt.Sprintf("dependencies[%d]", k), "job %q not found", dep)
+			}
+		}
+	}
+	if err := e.err(); err != nil {
+		return nil, err
+	}
+
+	// DFS 后序遍历得到拓扑序；visiting 中的节点再次被访问说明有环，
+	// 错误记在形成环的那条依赖上，然后跳过它继续遍历，一次报告所有的环
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(jobs))
+	sorted := make([]*Job, 0, len(jobs))
+	var path []string
+
+	var visit func(i int)
+	visit = func(i int) {
+		job := jobs[i]
+		state[job.ID] = visiting
+		path = append(path, job.ID)
+		for k, dep := range job.Dependencies {
+			j, ok := index[dep]
+			if !ok {
+				continue // 之前提交过的任务
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(j)
+			case visiting:
+				start := 0
+				for p, id := range path {
+					if id == dep {
+						start = p
+					}
+				}
+				cycle := append(append([]string{}, path[start:]...), dep)
+				e.prefix = fmt.Sprintf("jobs[%d].", i)
+				e.add(fmt.Sprintf("dependencies[%d]", k), "dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+		path = path[:len(path)-1]
+		state[job.ID] = done
+		sorted = append(sorted, job)
+	}
+
+	for i, job := range jobs {
+		if state[job.ID] == unvisited {
+			visit(i)
+		}
+	}
+	if err := e.err(); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
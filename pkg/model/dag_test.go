@@ -0,0 +1,101 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func dagJob(id string, deps ...string) *Job {
+	return &Job{ID: id, Dependencies: deps}
+}
+
+func TestSortByDependencies(t *testing.T) {
+	known := func(id string) bool { return id == "submitted" }
+	tests := []struct {
+		name       string
+		jobs       []*Job
+		wantOrder  []string
+		wantFields map[string]string // 字段路径 -> 原因中应当包含的内容
+	}{
+		{
+			name:      "chain",
+			jobs:      []*Job{dagJob("c", "b"), dagJob("b", "a"), dagJob("a")},
+			wantOrder: []string{"a", "b", "c"},
+		},
+		{
+			name:      "diamond",
+			jobs:      []*Job{dagJob("d", "b", "c"), dagJob("b", "a"), dagJob("c", "a"), dagJob("a")},
+			wantOrder: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "previously submitted dependency",
+			jobs:      []*Job{dagJob("a", "submitted")},
+			wantOrder: []string{"a"},
+		},
+		{
+			name:       "unknown dependency",
+			jobs:       []*Job{dagJob("a"), dagJob("b", "a", "missing")},
+			wantFields: map[string]string{"jobs[1].dependencies[1]": `"missing" not found`},
+		},
+		{
+			name:       "self dependency",
+			jobs:       []*Job{dagJob("a", "a")},
+			wantFields: map[string]string{"jobs[0].dependencies[0]": "a -> a"},
+		},
+		{
+			name:       "cycle",
+			jobs:       []*Job{dagJob("a", "c"), dagJob("b", "a"), dagJob("c", "b"), dagJob("d", "a")},
+			wantFields: map[string]string{"jobs[1].dependencies[0]": "a -> c -> b -> a"},
+		},
+		{
+			name: "two cycles",
+			jobs: []*Job{dagJob("a", "b"), dagJob("b", "a"), dagJob("c", "d"), dagJob("d", "c")},
+			wantFields: map[string]string{
+				"jobs[1].dependencies[0]": "a -> b -> a",
+				"jobs[3].dependencies[0]": "c -> d -> c",
+			},
+		},
+		{
+			name:       "duplicate id",
+			jobs:       []*Job{dagJob("a"), dagJob("a")},
+			wantFields: map[string]string{"jobs[1].id": "duplicate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := SortByDependencies(tt.jobs, known)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("SortByDependencies() error = %v", err)
+				}
+				var order []string
+				for _, job := range sorted {
+					order = append(order, job.ID)
+				}
+				if !reflect.DeepEqual(order, tt.wantOrder) {
+					t.Fatalf("order = %v, want %v", order, tt.wantOrder)
+				}
+				return
+			}
+
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("SortByDependencies() error = %v, want a ValidationError", err)
+			}
+			got := make(map[string]string)
+			for _, f := range invalid.Fields {
+				got[f.Field] = f.Reason
+			}
+			if len(got) != len(tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+			for field, want := range tt.wantFields {
+				if !strings.Contains(got[field], want) {
+					t.Errorf("%s = %q, want it to contain %q", field, got[field], want)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,9 @@
 package model
-import "time"
+import (
+    "fmt"
+    "strings"
+    "time"
+)
 type JobType string
 
 const(
@@ -7,6 +11,15 @@ const(
 	JobTypeDocker JobType = "DOCKER"
 )
 
+// PullPolicy 镜像拉取策略 (语义同 Kubernetes imagePullPolicy)
+type PullPolicy string
+
+const(
+	PullAlways       PullPolicy = "Always"       // 每次运行前都拉取
+	PullIfNotPresent PullPolicy = "IfNotPresent" // 本地没有才拉取 (默认)
+	PullNever        PullPolicy = "Never"        // 只使用本地镜像
+)
+
 type JobState int
 
 const(
@@ -16,19 +29,97 @@ const(
     JobSuccess                   // 运行成功
     JobFailed                    // 运行失败
     JobCancelled                 // 被取消
+    JobSkipped                   // 上游依赖失败，按 DependencySkip 策略跳过
 )
 
+var jobStateNames = map[JobState]string{
+    JobPending:   "Pending",
+    JobScheduled: "Scheduled",
+    JobRunning:   "Running",
+    JobSuccess:   "Success",
+    JobFailed:    "Failed",
+    JobCancelled: "Cancelled",
+    JobSkipped:   "Skipped",
+}
+
+func (s JobState) String() string {
+    if name, ok := jobStateNames[s]; ok {
+        return name
+    }
+    return "Unknown"
+}
+
+// ParseJobState 把状态名 (不区分大小写，如 "running") 解析为 JobState
+func ParseJobState(name string) (JobState, error) {
+    for state, stateName := range jobStateNames {
+        if strings.EqualFold(stateName, name) {
+            return state, nil
+        }
+    }
+    return 0, fmt.Errorf("unknown job state %q", name)
+}
+
+// IsTerminal 任务是否已经结束 (不会再发生状态变化)
+func (s JobState) IsTerminal() bool {
+    return s == JobSuccess || s == JobFailed || s == JobCancelled || s == JobSkipped
+}
+
+// 任务失败/取消的原因 (Status.Reason)，供程序判断；给人看的详细信息在 Status.Error
+const(
+    ReasonError            = "Error"            // 没能运行起来，或非零退出码
+    ReasonOOMKilled        = "OOMKilled"        // 超出内存限制
+    ReasonDeadlineExceeded = "DeadlineExceeded" // 超过 Spec.ActiveDeadlineSeconds
+    ReasonCancelled        = "Cancelled"        // 被用户取消
+    ReasonNodeLost         = "NodeLost"         // 运行任务的节点失联
+    ReasonDependencyFailed = "DependencyFailed" // 上游依赖没有成功
+)
+
+// DependencyPolicy 上游依赖失败 (Failed/Cancelled/Skipped) 时，下游任务怎么处理
+type DependencyPolicy string
+
+const(
+    DependencyFail DependencyPolicy = "Fail" // 下游任务标记为 Failed (默认)
+    DependencySkip DependencyPolicy = "Skip" // 下游任务标记为 Skipped
+)
+
+// 失败重试的默认退避时间 (Spec 中没有指定时使用)：10s, 20s, 40s ... 最长 5 分钟
+const(
+    DefaultRetryBackoff    = 10 * time.Second
+    DefaultMaxRetryBackoff = 5 * time.Minute
+)
+
+// Attempt 任务的一次执行记录
+type Attempt struct {
+    NodeID    string    `json:"node_id"`
+    ExitCode  int       `json:"exit_code"`
+    Error     string    `json:"error,omitempty"`
+    Reason    string    `json:"reason,omitempty"`
+    StartTime time.Time `json:"start_time"`
+    EndTime   time.Time `json:"end_time"`
+}
+
 type Job struct {
     ID          string            `json:"id"`
     Name        string            `json:"name"`
     Type        JobType           `json:"type"`
+
+    // 标签 (用于查询过滤，如 team=ml)
+    Labels      map[string]string `json:"labels,omitempty"`
+
+    // 提交时间 (CreateJob 时由 Store 填充)
+    CreateTime  time.Time         `json:"create_time"`
     
     // 任务的具体规格
     Spec struct {
         Image      string   `json:"image,omitempty"` // Docker 镜像 (如: alpine:latest)
+        ImagePullPolicy PullPolicy `json:"image_pull_policy,omitempty"` // 镜像拉取策略，为空时等同 IfNotPresent
         Command    []string `json:"command"`         // 执行命令 (如: ["echo", "hello"])
         Envs       []string `json:"envs"`            // 环境变量
+        WorkDir    string   `json:"work_dir,omitempty"` // Shell 任务的工作目录 (为空时 Worker 自动创建临时目录)
         RetryCount int      `json:"retry_count"`     // 容错机制：最大重试次数
+        RetryBackoffSeconds    int64 `json:"retry_backoff_seconds,omitempty"`     // 第一次重试前等待的秒数，之后每次翻倍 (默认 10)
+        MaxRetryBackoffSeconds int64 `json:"max_retry_backoff_seconds,omitempty"` // 重试等待时间的上限 (默认 300)
+        ActiveDeadlineSeconds int64 `json:"active_deadline_seconds,omitempty"` // 最长运行时间 (秒)，超时由 Worker 杀掉并标记失败，0 表示不限制
     } `json:"spec"`
 
     // 资源需求 (Scheduler 根据这个找 Node)
@@ -39,13 +130,76 @@ type Job struct {
     Status struct {
         State     JobState  `json:"state"`
         NodeID    string    `json:"node_id,omitempty"` // 被分配到了哪个节点
+        Retries   int       `json:"retries"`           // 已经重试的次数 (上限是 Spec.RetryCount)
         ExitCode  int       `json:"exit_code"`
         Error     string    `json:"error,omitempty"`
+        Reason    string    `json:"reason,omitempty"`  // 失败/取消的原因 (Reason* 常量)
+
+        // 失败重试：每次执行的记录，以及退回 Pending 后最早可以重新调度的时间
+        Attempts      []Attempt `json:"attempts,omitempty"`
+        NextRetryTime time.Time `json:"next_retry_time"`
         StartTime time.Time `json:"start_time"`
         EndTime   time.Time `json:"end_time"`
+
+        // 实际资源用量 (由执行器统计，统计不到时为 0)
+        PeakMemory int64 `json:"peak_memory,omitempty"` // 内存峰值 (字节)
+        CPUTimeMs  int64 `json:"cpu_time_ms,omitempty"` // 累计 CPU 时间 (毫秒)
     } `json:"status"`
 
+    // 乐观锁版本号 (对应 Etcd 的 ModRevision)，由 Store 在读取时填充
+    // 使用 CompareAndSwapJob 写入时，只有版本号一致才会成功
+    ResourceVersion int64 `json:"resource_version,omitempty"`
+
     // DAG 依赖支持
     // 含金量点：任务编排的核心，必须等 Dependencies 里的 ID 都 Success 才能跑
     Dependencies []string `json:"dependencies"` 
-}
\ No newline at end of file
+    DependencyPolicy DependencyPolicy `json:"dependency_policy,omitempty"` // 依赖失败时的处理策略，为空时等同 Fail
+}
+
+// RetryBackoff 第 n 次重试 (从 1 开始) 之前需要等待的时间：Base * 2^(n-1)，不超过 Max
+func (j *Job) RetryBackoff(n int) time.Duration {
+    backoff := DefaultRetryBackoff
+    if j.Spec.RetryBackoffSeconds > 0 {
+        backoff = time.Duration(j.Spec.RetryBackoffSeconds) * time.Second
+    }
+    maxBackoff := DefaultMaxRetryBackoff
+    if j.Spec.MaxRetryBackoffSeconds > 0 {
+        maxBackoff = time.Duration(j.Spec.MaxRetryBackoffSeconds) * time.Second
+    }
+
+    for i := 1; i < n && backoff < maxBackoff; i++ {
+        backoff *= 2
+    }
+    if backoff > maxBackoff {
+        backoff = maxBackoff
+    }
+    return backoff
+}
+
+// FinishAttempt 一次执行结束 (Status 里已经写好了最终的 State/ExitCode/Error/Reason)
+// 把这次执行记入 Status.Attempts；如果失败且还没用完 Spec.RetryCount，
+// 就退回 Pending、清空节点和上一次的结果，并在退避之后才允许重新调度，此时返回 true
+func (j *Job) FinishAttempt(now time.Time) bool {
+    j.Status.Attempts = append(j.Status.Attempts, Attempt{
+        NodeID:    j.Status.NodeID,
+        ExitCode:  j.Status.ExitCode,
+        Error:     j.Status.Error,
+        Reason:    j.Status.Reason,
+        StartTime: j.Status.StartTime,
+        EndTime:   now,
+    })
+    if j.Status.State != JobFailed || j.Status.Retries >= j.Spec.RetryCount {
+        return false
+    }
+
+    j.Status.Retries++
+    j.Status.State = JobPending
+    j.Status.NodeID = ""
+    // 上一次的结果已经记在 Attempts 里，不能留在重新排队的任务上
+    j.Status.ExitCode = 0
+    j.Status.Error = ""
+    j.Status.Reason = ""
+    j.Status.EndTime = time.Time{}
+    j.Status.NextRetryTime = now.Add(j.RetryBackoff(j.Status.Retries))
+    return true
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFinishAttempt(t *testing.T) {
+	tests := []struct {
+		name       string
+		retries    int
+		retryCount int
+		wantRetry  bool
+	}{
+		{"retry left", 0, 2, true},
+		{"last retry", 2, 2, false},
+		{"no retries", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			job := validJob()
+			job.Spec.RetryCount = tt.retryCount
+			job.Status.Retries = tt.retries
+			job.Status.State = JobFailed
+			job.Status.NodeID = "node-1"
+			job.Status.ExitCode = 137
+			job.Status.Error = "node node-1 went offline while job was RUNNING"
+			job.Status.Reason = ReasonNodeLost
+			job.Status.EndTime = now
+
+			if got := job.FinishAttempt(now); got != tt.wantRetry {
+				t.Fatalf("FinishAttempt() = %v, want %v", got, tt.wantRetry)
+			}
+			if n := len(job.Status.Attempts); n != 1 {
+				t.Fatalf("len(Attempts) = %d, want 1", n)
+			}
+			if a := job.Status.Attempts[0]; a.ExitCode != 137 || a.Reason != ReasonNodeLost || a.Error == "" || a.NodeID != "node-1" {
+				t.Errorf("attempt = %+v, want the failed execution", a)
+			}
+
+			st := job.Status
+			if tt.wantRetry {
+				if st.State != JobPending || st.NodeID != "" || st.ExitCode != 0 || st.Error != "" || st.Reason != "" || !st.EndTime.IsZero() {
+					t.Errorf("re-queued status = %+v, want Pending without the previous result", st)
+				}
+				if st.Retries != tt.retries+1 || !st.NextRetryTime.After(now) {
+					t.Errorf("retries %d next retry %v, want %d after now", st.Retries, st.NextRetryTime, tt.retries+1)
+				}
+			} else if st.State != JobFailed || st.ExitCode != 137 || st.Reason != ReasonNodeLost {
+				t.Errorf("final status = %+v, want the failure kept", st)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// 任务输出来自哪个流
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// LogLine 任务输出的一行
+type LogLine struct {
+	Time   time.Time `json:"time"`   // Worker 收到这一行的时间
+	Stream string    `json:"stream"` // StreamStdout / StreamStderr
+	Text   string    `json:"text"`   // 不含结尾的换行符
+}
+
+// LogChunk 一段任务输出
+// Worker 在任务运行过程中按大小/时间攒批写入，单个 Chunk 的大小有上限
+type LogChunk struct {
+	JobID   string    `json:"job_id"`
+	Attempt int       `json:"attempt"` // 第几次执行 (Status.Retries)，重试产生的输出不会覆盖之前的
+	Seq     int64     `json:"seq"`     // 同一次执行内的序号，从 0 开始递增
+	Lines   []LogLine `json:"lines"`
+}
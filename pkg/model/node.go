@@ -1,5 +1,10 @@
 package model
 
+import "time"
+
+// NodeHeartbeatTimeout 超过这个时间没有心跳，Master 就认为节点已经宕机 (OFFLINE)
+const NodeHeartbeatTimeout = 10 * time.Second
+
 // NodeStatus 节点健康状态
 type NodeStatus string
 
@@ -11,7 +16,10 @@ const (
 type Node struct {
     ID      string     `json:"id"`       // 唯一标识，通常是 UUID 或 Hostname
     IP      string     `json:"ip"`       // Worker 的 IP 地址，用于 gRPC 通信
+    Port    int        `json:"port,omitempty"` // WorkerService 的端口，0 表示节点不接受推送 (只通过 Watch 领取任务)
     Version string     `json:"version"`  // Worker 版本号
+    LogAddr string     `json:"log_addr,omitempty"` // 日志读取接口的地址 (fs 日志后端，如 http://10.0.0.5:9091)
+    JobTypes []JobType `json:"job_types,omitempty"` // 节点能执行的任务类型 (没有 Docker 的节点只有 SHELL)
     
     // 资源视图
     // Total: 物理机总资源
@@ -22,4 +30,25 @@ type Node struct {
 
     Status         NodeStatus `json:"status"`
     LastHeartbeat  int64      `json:"last_heartbeat"` // Unix 时间戳
-}
\ No newline at end of file
+}
+
+// Supports 节点是否能执行这种类型的任务 (没有上报类型的节点只能执行 SHELL 任务)
+func (n *Node) Supports(t JobType) bool {
+    if t == "" {
+        t = JobTypeShell
+    }
+    if len(n.JobTypes) == 0 {
+        return t == JobTypeShell
+    }
+    for _, supported := range n.JobTypes {
+        if supported == t {
+            return true
+        }
+    }
+    return false
+}
+
+// IsStale 判断节点心跳是否已经过期
+func (n *Node) IsStale(now time.Time) bool {
+    return now.Sub(time.Unix(n.LastHeartbeat, 0)) > NodeHeartbeatTimeout
+}
@@ -0,0 +1,223 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 没有声明资源需求时使用的默认值
+const (
+	DefaultMilliCPU = 100       // 0.1 核
+	DefaultMemory   = 128 << 20 // 128 MiB
+)
+
+const (
+	maxJobIDLength   = 128
+	maxJobNameLength = 253
+)
+
+// jobIDPattern 任务 ID 会出现在存储的 Key 和 Worker 的日志目录里，只允许字母、数字和 . _ -，首尾必须是字母或数字
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+
+// FieldError 一个不合法的字段
+type FieldError struct {
+	Field  string `json:"field"`  // 字段路径 (JSON 字段名)，如 spec.command、res_req.memory、jobs[1].dependencies[0]
+	Reason string `json:"reason"` // 给人看的原因
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationError 任务 (或一批任务) 没有通过校验，Fields 列出所有不合法的字段
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		reasons = append(reasons, f.Error())
+	}
+	return "invalid job: " + strings.Join(reasons, "; ")
+}
+
+// fieldErrors 收集校验过程中发现的错误
+type fieldErrors struct {
+	prefix string
+	fields []*FieldError
+}
+
+func (e *fieldErrors) add(field, format string, args ...any) {
+	e.fields = append(e.fields, &FieldError{Field: e.prefix + field, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (e *fieldErrors) err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: e.fields}
+}
+
+// NewJobID 生成一个随机的任务 ID (提交时没有指定 ID 的任务使用)
+func NewJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "job-" + hex.EncodeToString(b)
+}
+
+// SetDefaults 补齐用户没有填写的字段：
+//   - 没有 ID 时生成一个，没有名字时使用 ID
+//   - 没有类型时，指定了镜像的是 DOCKER 任务，否则是 SHELL 任务
+//   - 没有声明的资源需求使用 DefaultMilliCPU / DefaultMemory
+//   - Docker 任务的拉取策略默认为 IfNotPresent，依赖失败的处理策略默认为 Fail
+func (j *Job) SetDefaults() {
+	if j.ID == "" {
+		j.ID = NewJobID()
+	}
+	if j.Name == "" {
+		j.Name = j.ID
+	}
+	if j.Type == "" {
+		j.Type = JobTypeShell
+		if j.Spec.Image != "" {
+			j.Type = JobTypeDocker
+		}
+	}
+	if j.ResReq.MilliCPU == 0 {
+		j.ResReq.MilliCPU = DefaultMilliCPU
+	}
+	if j.ResReq.Memory == 0 {
+		j.ResReq.Memory = DefaultMemory
+	}
+	if j.Type == JobTypeDocker && j.Spec.ImagePullPolicy == "" {
+		j.Spec.ImagePullPolicy = PullIfNotPresent
+	}
+	if len(j.Dependencies) > 0 && j.DependencyPolicy == "" {
+		j.DependencyPolicy = DependencyFail
+	}
+}
+
+// Validate 检查用户提交的任务，返回 *ValidationError (包含所有不合法的字段)
+// 只检查任务本身；依赖的任务是否存在、是否有环由提交时结合存储中的数据检查
+func (j *Job) Validate() error {
+	e := &fieldErrors{}
+	j.validate(e)
+	return e.err()
+}
+
+// ValidateJobs 检查一批一起提交的任务 (工作流)，字段路径带上 jobs[i] 前缀
+// 除了每个任务本身，还检查同一批里的 ID 不能重复
+func ValidateJobs(jobs []*Job) error {
+	e := &fieldErrors{}
+	seen := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		e.prefix = fmt.Sprintf("jobs[%d].", i)
+		job.validate(e)
+		if first, dup := seen[job.ID]; dup && job.ID != "" {
+			e.add("id", "duplicate id %q (also used by jobs[%d])", job.ID, first)
+			continue
+		}
+		seen[job.ID] = i
+	}
+	return e.err()
+}
+
+func (j *Job) validate(e *fieldErrors) {
+	switch {
+	case j.ID == "":
+		e.add("id", "must not be empty")
+	case len(j.ID) > maxJobIDLength:
+		e.add("id", "must be at most %d characters", maxJobIDLength)
+	case !jobIDPattern.MatchString(j.ID):
+		e.add("id", "must contain only letters, digits, '.', '_' or '-', and start and end with a letter or digit")
+	}
+	if len(j.Name) > maxJobNameLength {
+		e.add("name", "must be at most %d characters", maxJobNameLength)
+	}
+	for k := range j.Labels {
+		if k == "" || strings.ContainsAny(k, "=,") {
+			e.add("labels", "invalid key %q: must not be empty or contain '=' or ','", k)
+		}
+	}
+
+	switch j.Type {
+	case JobTypeShell:
+		if j.Spec.Image != "" {
+			e.add("spec.image", "only DOCKER jobs can specify an image")
+		}
+	case JobTypeDocker:
+		if j.Spec.Image == "" {
+			e.add("spec.image", "required for DOCKER jobs")
+		}
+	default:
+		e.add("type", "unknown type %q (must be %s or %s)", j.Type, JobTypeShell, JobTypeDocker)
+	}
+	j.validateSpec(e)
+
+	if j.ResReq.MilliCPU < 0 {
+		e.add("res_req.milli_cpu", "must not be negative")
+	}
+	if j.ResReq.Memory < 0 {
+		e.add("res_req.memory", "must not be negative")
+	}
+
+	seen := make(map[string]bool, len(j.Dependencies))
+	for i, dep := range j.Dependencies {
+		field := fmt.Sprintf("dependencies[%d]", i)
+		switch {
+		case dep == "":
+			e.add(field, "must not be empty")
+		case dep == j.ID:
+			e.add(field, "a job cannot depend on itself")
+		case seen[dep]:
+			e.add(field, "duplicate dependency %q", dep)
+		}
+		seen[dep] = true
+	}
+	switch j.DependencyPolicy {
+	case "", DependencyFail, DependencySkip:
+	default:
+		e.add("dependency_policy", "unknown policy %q (must be %s or %s)", j.DependencyPolicy, DependencyFail, DependencySkip)
+	}
+}
+
+func (j *Job) validateSpec(e *fieldErrors) {
+	spec := &j.Spec
+	if len(spec.Command) == 0 {
+		e.add("spec.command", "must not be empty")
+	} else if spec.Command[0] == "" {
+		e.add("spec.command[0]", "must not be empty")
+	}
+	for i, env := range spec.Envs {
+		if name, _, ok := strings.Cut(env, "="); !ok || name == "" {
+			e.add(fmt.Sprintf("spec.envs[%d]", i), "must be in NAME=VALUE form")
+		}
+	}
+	switch spec.ImagePullPolicy {
+	case "", PullAlways, PullIfNotPresent, PullNever:
+	default:
+		e.add("spec.image_pull_policy", "unknown policy %q (must be %s, %s or %s)",
+			spec.ImagePullPolicy, PullAlways, PullIfNotPresent, PullNever)
+	}
+	if spec.WorkDir != "" && !filepath.IsAbs(spec.WorkDir) {
+		e.add("spec.work_dir", "must be an absolute path")
+	}
+
+	if spec.RetryCount < 0 {
+		e.add("spec.retry_count", "must not be negative")
+	}
+	if spec.RetryBackoffSeconds < 0 {
+		e.add("spec.retry_backoff_seconds", "must not be negative")
+	}
+	if spec.MaxRetryBackoffSeconds < 0 {
+		e.add("spec.max_retry_backoff_seconds", "must not be negative")
+	}
+	if spec.ActiveDeadlineSeconds < 0 {
+		e.add("spec.active_deadline_seconds", "must not be negative")
+	}
+}
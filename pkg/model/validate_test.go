@@ -0,0 +1,126 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// validJob 一个能通过校验的 SHELL 任务，各用例在它的基础上修改
+func validJob() *Job {
+	job := &Job{ID: "job-1"}
+	job.Spec.Command = []string{"echo", "hi"}
+	job.SetDefaults()
+	return job
+}
+
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error = %v, want a ValidationError", err)
+	}
+	fields := make([]string, 0, len(invalid.Fields))
+	for _, f := range invalid.Fields {
+		fields = append(fields, f.Field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestJobValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(j *Job)
+		want   []string // 不合法的字段 (排序后)
+	}{
+		{"valid", func(j *Job) {}, nil},
+		{"empty id", func(j *Job) { j.ID = "" }, []string{"id"}},
+		{"id with slash", func(j *Job) { j.ID = "a/b" }, []string{"id"}},
+		{"id too long", func(j *Job) { j.ID = strings.Repeat("a", maxJobIDLength+1) }, []string{"id"}},
+		{"bad label key", func(j *Job) { j.Labels = map[string]string{"a=b": "c"} }, []string{"labels"}},
+		{"unknown type", func(j *Job) { j.Type = "VM" }, []string{"type"}},
+		{"shell with image", func(j *Job) { j.Spec.Image = "alpine" }, []string{"spec.image"}},
+		{"docker without image", func(j *Job) { j.Type = JobTypeDocker }, []string{"spec.image"}},
+		{"empty command", func(j *Job) { j.Spec.Command = nil }, []string{"spec.command"}},
+		{"empty program", func(j *Job) { j.Spec.Command = []string{"", "x"} }, []string{"spec.command[0]"}},
+		{"bad env", func(j *Job) { j.Spec.Envs = []string{"A=1", "B"} }, []string{"spec.envs[1]"}},
+		{"relative work dir", func(j *Job) { j.Spec.WorkDir = "tmp" }, []string{"spec.work_dir"}},
+		{"unknown pull policy", func(j *Job) { j.Spec.ImagePullPolicy = "Sometimes" }, []string{"spec.image_pull_policy"}},
+		{
+			name: "negative numbers",
+			modify: func(j *Job) {
+				j.ResReq = Resource{MilliCPU: -1, Memory: -1}
+				j.Spec.RetryCount = -1
+				j.Spec.ActiveDeadlineSeconds = -1
+			},
+			want: []string{"res_req.memory", "res_req.milli_cpu", "spec.active_deadline_seconds", "spec.retry_count"},
+		},
+		{
+			name:   "bad dependencies",
+			modify: func(j *Job) { j.Dependencies = []string{"a", "", "job-1", "a"} },
+			want:   []string{"dependencies[1]", "dependencies[2]", "dependencies[3]"},
+		},
+		{"unknown dependency policy", func(j *Job) { j.DependencyPolicy = "Ignore" }, []string{"dependency_policy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := validJob()
+			tt.modify(job)
+			if got := invalidFields(t, job.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invalid fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJobs(t *testing.T) {
+	a, b, c := validJob(), validJob(), validJob()
+	a.ID, b.ID, c.ID = "a", "b", "a"
+	b.Spec.Command = nil
+
+	got := invalidFields(t, ValidateJobs([]*Job{a, b, c}))
+	want := []string{"jobs[1].spec.command", "jobs[2].id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("invalid fields = %v, want %v", got, want)
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(j *Job)
+		want  func(j *Job) bool
+	}{
+		{"generates id and name", func(j *Job) {}, func(j *Job) bool { return strings.HasPrefix(j.ID, "job-") && j.Name == j.ID }},
+		{"keeps id", func(j *Job) { j.ID = "mine" }, func(j *Job) bool { return j.ID == "mine" && j.Name == "mine" }},
+		{"shell by default", func(j *Job) {}, func(j *Job) bool { return j.Type == JobTypeShell && j.Spec.ImagePullPolicy == "" }},
+		{"docker when image is set", func(j *Job) { j.Spec.Image = "alpine" }, func(j *Job) bool {
+			return j.Type == JobTypeDocker && j.Spec.ImagePullPolicy == PullIfNotPresent
+		}},
+		{"default resources", func(j *Job) {}, func(j *Job) bool {
+			return j.ResReq == Resource{MilliCPU: DefaultMilliCPU, Memory: DefaultMemory}
+		}},
+		{"keeps resources", func(j *Job) { j.ResReq = Resource{MilliCPU: 1, Memory: 2} }, func(j *Job) bool {
+			return j.ResReq == Resource{MilliCPU: 1, Memory: 2}
+		}},
+		{"dependency policy", func(j *Job) { j.Dependencies = []string{"a"} }, func(j *Job) bool {
+			return j.DependencyPolicy == DependencyFail
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{}
+			tt.setup(job)
+			job.SetDefaults()
+			if !tt.want(job) {
+				t.Errorf("SetDefaults() = %+v", job)
+			}
+		})
+	}
+}
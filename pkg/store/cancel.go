@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// CancelJob 把任务标记为 Cancelled，任何状态都可以取消
+//   - Pending：调度器 Watch 到状态变化后把它移出调度队列
+//   - Scheduled/Running：Worker Watch 到后停止进程/容器 (带优雅退出时间)
+//   - 已经结束的任务保持原样，调用方根据返回的状态判断
+//
+// 使用 CAS 写入，和调度器 Bind / Worker 上报并发时以重新读取的最新状态为准
+func CancelJob(ctx context.Context, s Store, jobID string) (*model.Job, error) {
+	for {
+		job, err := s.GetJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status.State.IsTerminal() {
+			return job, nil
+		}
+
+		job.Status.State = model.JobCancelled
+		job.Status.Error = "cancelled by user"
+		job.Status.Reason = model.ReasonCancelled
+		job.Status.EndTime = time.Now()
+		err = s.CompareAndSwapJob(ctx, job)
+		if !IsConflict(err) {
+			return job, err
+		}
+	}
+}
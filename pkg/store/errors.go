@@ -0,0 +1,42 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound Key 不存在
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists 创建的对象已经存在 (CreateJob 不会覆盖同 ID 的任务)
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrCompacted Watch 的起始 Revision 已经被压缩，无法回放
+	// 调用方需要重新 List 拿到最新状态，再从新的 Revision 开始 Watch
+	ErrCompacted = errors.New("revision has been compacted")
+
+	// ErrConflict 乐观锁冲突：对象在读取之后已经被别人修改过
+	// 调用方应当重新读取最新版本后再决定是否重试
+	ErrConflict = errors.New("resource version conflict")
+)
+
+// ConflictError 描述一次失败的 Compare-And-Swap 写入
+// 可以用 errors.Is(err, ErrConflict) 判断
+type ConflictError struct {
+	Key             string
+	ResourceVersion int64 // 调用方期望的版本 (0 表示期望 Key 不存在)
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: %s (expected version %d)", ErrConflict, e.Key, e.ResourceVersion)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// IsConflict 判断是否为乐观锁冲突
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
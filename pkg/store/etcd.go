@@ -3,12 +3,15 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"titan/pkg/model"
 
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -16,10 +19,45 @@ import (
 const (
 	JobKeyPrefix  = "/titan/jobs/"
 	NodeKeyPrefix = "/titan/nodes/"
+	LogKeyPrefix  = "/titan/logs/"
+)
+
+// logKeyPrefixFor 某个任务所有日志块的公共前缀 (以 / 结尾，避免 job-1 匹配到 job-10 的日志)
+func logKeyPrefixFor(jobID string) string {
+	return LogKeyPrefix + jobID + "/"
+}
+
+// logChunkKey 日志块的 Key：/titan/logs/<jobID>/<attempt>/<seq>
+// 数字补零，按 Key 排序就是写入顺序
+func logChunkKey(chunk *model.LogChunk) string {
+	return fmt.Sprintf("%s%06d/%010d", logKeyPrefixFor(chunk.JobID), chunk.Attempt, chunk.Seq)
+}
+
+// Etcd 中的日志绑定在租约上自动过期 (日志后端为 etcd 时)
+// 同一时段写入的日志共用一个租约，每过 logLeaseRotation 换一个新的，避免每个日志块申请一次
+// 租约时长为 logRetention + logLeaseRotation，保证每块日志至少保留 logRetention
+const (
+	logRetention     = 72 * time.Hour
+	logLeaseRotation = 1 * time.Hour
+)
+
+// Watch 中断后的重试策略
+const (
+	maxWatchRetries    = 5
+	watchRetryInterval = 1 * time.Second
 )
 
 type EtcdManager struct {
 	client *clientv3.Client
+
+	// 每个节点一个租约 (nodeID -> LeaseID)，心跳时续约
+	leaseMu    sync.Mutex
+	nodeLeases map[string]clientv3.LeaseID
+
+	// 当前写日志用的租约 (logLeaseAt 为申请时间)
+	logLeaseMu sync.Mutex
+	logLease   clientv3.LeaseID
+	logLeaseAt time.Time
 }
 
 // NewEtcdManager 初始化 Etcd 连接
@@ -31,7 +69,10 @@ func NewEtcdManager(endpoints []string) (*EtcdManager, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &EtcdManager{client: cli}, nil
+	return &EtcdManager{
+		client:     cli,
+		nodeLeases: make(map[string]clientv3.LeaseID),
+	}, nil
 }
 
 // ---------------------------------------------------------
@@ -39,13 +80,152 @@ func NewEtcdManager(endpoints []string) (*EtcdManager, error) {
 // ---------------------------------------------------------
 
 func (e *EtcdManager) CreateJob(ctx context.Context, job *model.Job) error {
-	key := JobKeyPrefix + job.ID
-	return e.putValue(ctx, key, job)
+	if job.CreateTime.IsZero() {
+		job.CreateTime = time.Now()
+	}
+	// ResourceVersion 为 0 的 CAS：只有 Key 不存在时才写入
+	job.ResourceVersion = 0
+	err := e.CompareAndSwapJob(ctx, job)
+	if IsConflict(err) {
+		return fmt.Errorf("job %s: %w", job.ID, ErrAlreadyExists)
+	}
+	return err
+}
+
+// CreateJobs 在一个 Txn 里写入所有任务：每个任务 Key 都要求不存在 (CreateRevision == 0)，
+// 每个 requires Key 都要求存在；条件不满足时在同一个 Txn 里读出这些 Key，判断是哪一个导致失败
+func (e *EtcdManager) CreateJobs(ctx context.Context, jobs []*model.Job, requires []string) error {
+	now := time.Now()
+	cmps := make([]clientv3.Cmp, 0, len(jobs)+len(requires))
+	puts := make([]clientv3.Op, 0, len(jobs))
+	gets := make([]clientv3.Op, 0, len(jobs)+len(requires))
+	for _, job := range jobs {
+		if job.CreateTime.IsZero() {
+			job.CreateTime = now
+		}
+		job.ResourceVersion = 0
+		key := JobKeyPrefix + job.ID
+		bytes, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+		puts = append(puts, clientv3.OpPut(key, string(bytes)))
+		gets = append(gets, clientv3.OpGet(key, clientv3.WithKeysOnly()))
+	}
+	for _, id := range requires {
+		key := JobKeyPrefix + id
+		cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), ">", 0))
+		gets = append(gets, clientv3.OpGet(key, clientv3.WithKeysOnly()))
+	}
+
+	resp, err := e.client.Txn(ctx).If(cmps...).Then(puts...).Else(gets...).Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		for i, r := range resp.Responses {
+			exists := len(r.GetResponseRange().Kvs) > 0
+			switch {
+			case i < len(jobs) && exists:
+				return fmt.Errorf("job %s: %w", jobs[i].ID, ErrAlreadyExists)
+			case i >= len(jobs) && !exists:
+				return fmt.Errorf("job %s: %w", requires[i-len(jobs)], ErrNotFound)
+			}
+		}
+		return fmt.Errorf("create %d jobs: %w", len(jobs), ErrConflict)
+	}
+	for _, job := range jobs {
+		job.ResourceVersion = resp.Header.Revision
+	}
+	return nil
 }
 
 func (e *EtcdManager) GetJob(ctx context.Context, id string) (*model.Job, error) {
-	// ... (代码略，逻辑类似 ListNodes，为了节省篇幅只写核心的)
-	return nil, nil
+	job, _, err := e.GetJobWithRevision(ctx, id)
+	return job, err
+}
+
+func (e *EtcdManager) GetJobWithRevision(ctx context.Context, id string) (*model.Job, int64, error) {
+	resp, err := e.client.Get(ctx, JobKeyPrefix+id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, fmt.Errorf("job %s: %w", id, ErrNotFound)
+	}
+
+	var job model.Job
+	if err := json.Unmarshal(resp.Kvs[0].Value, &job); err != nil {
+		return nil, 0, err
+	}
+	job.ResourceVersion = resp.Kvs[0].ModRevision
+	return &job, resp.Header.Revision, nil
+}
+
+// ListJobs 按条件分页查询任务
+// 过滤在客户端完成：按 Key 顺序分批读取，直到凑满 Limit 条或读完
+// 翻页时固定使用第一页的 Revision 读取，保证整个分页过程看到的是同一个快照
+func (e *EtcdManager) ListJobs(ctx context.Context, filter *JobFilter) (*JobList, error) {
+	if filter == nil {
+		filter = &JobFilter{}
+	}
+
+	startKey := JobKeyPrefix
+	endKey := clientv3.GetPrefixRangeEnd(JobKeyPrefix)
+	var revision int64
+	if filter.Continue != "" {
+		ct, err := decodeContinue(filter.Continue)
+		if err != nil {
+			return nil, err
+		}
+		startKey = ct.LastKey + "\x00"
+		revision = ct.Revision
+	}
+
+	list := &JobList{Jobs: make([]*model.Job, 0)}
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(endKey), clientv3.WithLimit(listBatchSize)}
+		if revision > 0 {
+			opts = append(opts, clientv3.WithRev(revision))
+		}
+		resp, err := e.client.Get(ctx, startKey, opts...)
+		if errors.Is(err, rpctypes.ErrCompacted) {
+			return nil, fmt.Errorf("%w: snapshot revision %d has been compacted, restart the listing", ErrInvalidContinue, revision)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if revision == 0 {
+			revision = resp.Header.Revision
+		}
+		list.Revision = revision
+
+		for i, kv := range resp.Kvs {
+			var job model.Job
+			if err := json.Unmarshal(kv.Value, &job); err != nil {
+				log.Printf("Failed to unmarshal job: %v", err)
+				continue
+			}
+			job.ResourceVersion = kv.ModRevision
+			if !filter.Match(&job) {
+				continue
+			}
+
+			list.Jobs = append(list.Jobs, &job)
+			if filter.Limit > 0 && len(list.Jobs) >= filter.Limit {
+				if i < len(resp.Kvs)-1 || resp.More {
+					list.Continue = encodeContinue(string(kv.Key), revision)
+				}
+				return list, nil
+			}
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			return list, nil
+		}
+		startKey = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
 }
 
 func (e *EtcdManager) UpdateJob(ctx context.Context, job *model.Job) error {
@@ -53,53 +233,213 @@ func (e *EtcdManager) UpdateJob(ctx context.Context, job *model.Job) error {
 	return e.putValue(ctx, key, job)
 }
 
+// CompareAndSwapJob 基于 ModRevision 的事务写入 (Txn: If ModRevision == rv Then Put)
+func (e *EtcdManager) CompareAndSwapJob(ctx context.Context, job *model.Job) error {
+	key := JobKeyPrefix + job.ID
+	bytes, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", job.ResourceVersion)).
+		Then(clientv3.OpPut(key, string(bytes))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return &ConflictError{Key: key, ResourceVersion: job.ResourceVersion}
+	}
+	job.ResourceVersion = resp.Header.Revision
+	return nil
+}
+
+func (e *EtcdManager) DeleteJob(ctx context.Context, id string) error {
+	_, err := e.client.Delete(ctx, JobKeyPrefix+id)
+	return err
+}
+
 // WatchJobs 核心难点：将 Etcd 的 Watch 转换为业务 Channel
-func (e *EtcdManager) WatchJobs(ctx context.Context) <-chan JobEvent {
+// - 每个事件都带 Revision，内部记录最后看到的 Revision
+// - Watch 因断线等原因中断时，从 lastRevision+1 自动续上，不丢不重
+// - 起始 Revision 已被压缩 (ErrCompacted) 或重试耗尽时，发送一个带 Err 的事件后关闭 Channel，由调用方重新 List
+func (e *EtcdManager) WatchJobs(ctx context.Context, fromRevision int64) <-chan JobEvent {
 	eventChan := make(chan JobEvent)
 
 	// 启动一个协程在后台一直监听
 	go func() {
-		// 监听 /titan/jobs/ 前缀下的所有变化
-		watchChan := e.client.Watch(ctx, JobKeyPrefix, clientv3.WithPrefix())
+		defer close(eventChan)
 
-		for watchResp := range watchChan {
-			for _, ev := range watchResp.Events {
-				var eventType JobEventType
-				switch ev.Type {
-				case clientv3.EventTypePut:
-					eventType = JobUpdate // 这里的 Create 和 Update 在 Etcd 都是 Put
-				case clientv3.EventTypeDelete:
-					eventType = JobDelete
+		nextRev := fromRevision
+		err := e.resumableWatch(ctx, "Job", JobKeyPrefix, &nextRev, func(ev *clientv3.Event) error {
+			var eventType JobEventType
+			kv := ev.Kv
+			switch ev.Type {
+			case clientv3.EventTypePut:
+				eventType = JobUpdate // 这里的 Create 和 Update 在 Etcd 都是 Put
+			case clientv3.EventTypeDelete:
+				eventType = JobDelete
+				if ev.PrevKv == nil {
+					return nil
 				}
+				kv = ev.PrevKv
+			}
 
-				// 反序列化 Job 数据
-				var job model.Job
-				if err := json.Unmarshal(ev.Kv.Value, &job); err != nil {
-					log.Printf("[Etcd] Failed to unmarshal job: %v", err)
-					continue
-				}
+			// 反序列化 Job 数据
+			var job model.Job
+			if err := json.Unmarshal(kv.Value, &job); err != nil {
+				log.Printf("[Etcd] Failed to unmarshal job: %v", err)
+				return nil
+			}
+			job.ResourceVersion = ev.Kv.ModRevision
 
-				// 发送给调度器
-				eventChan <- JobEvent{
-					Type: eventType,
-					Job:  &job,
-				}
+			// 发送给调度器
+			select {
+			case eventChan <- JobEvent{Type: eventType, Job: &job, Revision: ev.Kv.ModRevision}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
 			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case eventChan <- JobEvent{Err: err, Revision: nextRev}:
+		case <-ctx.Done():
 		}
-		close(eventChan)
 	}()
 
 	return eventChan
 }
 
+// resumableWatch 监听 prefix 下的变化，把每个事件交给 handle，直到 Watch 无法恢复
+// 中断时等待一会儿从 nextRev 续上 (nextRev 为 0 表示从建立 Watch 时开始)；
+// 起始 Revision 已被压缩 (ErrCompacted) 或连续 maxWatchRetries 次没有收到任何响应时返回最后的错误
+func (e *EtcdManager) resumableWatch(ctx context.Context, what, prefix string, nextRev *int64, handle func(ev *clientv3.Event) error) error {
+	retries := 0
+	for {
+		progressed, err := e.watchOnce(ctx, prefix, nextRev, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if progressed {
+			retries = 0
+		}
+
+		if errors.Is(err, ErrCompacted) || retries >= maxWatchRetries {
+			log.Printf("[Etcd] %s watch terminated at revision %d: %v", what, *nextRev, err)
+			return err
+		}
+
+		retries++
+		log.Printf("[Etcd] %s watch interrupted (%v), resuming from revision %d (retry %d/%d)",
+			what, err, *nextRev, retries, maxWatchRetries)
+		select {
+		case <-time.After(time.Duration(retries) * watchRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// watchOnce 建立一次 Watch 并持续转发事件，直到 Watch 中断
+// nextRev 会随着事件推进，中断后调用方可以从这里续上；progressed 表示本次 Watch 是否收到过响应
+func (e *EtcdManager) watchOnce(ctx context.Context, prefix string, nextRev *int64, handle func(ev *clientv3.Event) error) (progressed bool, err error) {
+	// WithRequireLeader：集群失去 Leader 时主动断开，而不是一直挂着收不到事件
+	wctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	// PrevKV：删除事件的 Value 为空，需要用删除前的值还原对象
+	opts := []clientv3.OpOption{
+		clientv3.WithPrefix(),
+		clientv3.WithPrevKV(),
+		clientv3.WithCreatedNotify(),
+		clientv3.WithProgressNotify(),
+	}
+	if *nextRev > 0 {
+		opts = append(opts, clientv3.WithRev(*nextRev))
+	}
+	watchChan := e.client.Watch(wctx, prefix, opts...)
+
+	for watchResp := range watchChan {
+		progressed = true
+
+		if watchResp.CompactRevision != 0 {
+			return progressed, fmt.Errorf("%w: requested revision %d, compacted up to %d",
+				ErrCompacted, *nextRev, watchResp.CompactRevision)
+		}
+		if err := watchResp.Err(); err != nil {
+			return progressed, err
+		}
+
+		// 建立成功：如果是"从现在开始"监听，记下起点，断线后从这里续上
+		if watchResp.Created {
+			if *nextRev == 0 {
+				*nextRev = watchResp.Header.Revision + 1
+			}
+			continue
+		}
+		// 进度通知：Header.Revision 之前的事件都已经送达，可以推进 Revision，减少重连后的回放量
+		if watchResp.IsProgressNotify() {
+			if watchResp.Header.Revision+1 > *nextRev {
+				*nextRev = watchResp.Header.Revision + 1
+			}
+			continue
+		}
+
+		for _, ev := range watchResp.Events {
+			*nextRev = ev.Kv.ModRevision + 1
+			if err := handle(ev); err != nil {
+				return progressed, err
+			}
+		}
+	}
+	return progressed, errors.New("watch channel closed")
+}
+
 // ---------------------------------------------------------
 // Node 相关实现
 // ---------------------------------------------------------
 
+// RegisterNode 把节点信息写入绑定了租约的 Key
+// 每次心跳都会续约；Worker 宕机后租约过期，节点记录被 Etcd 自动删除
 func (e *EtcdManager) RegisterNode(ctx context.Context, node *model.Node) error {
 	key := NodeKeyPrefix + node.ID
-	// 这里通常需要加上 Lease (租约)，为了简化先不写
-	return e.putValue(ctx, key, node)
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	leaseID, err := e.keepNodeLease(ctx, node.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.client.Put(ctx, key, string(bytes), clientv3.WithLease(leaseID))
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		// 续约和 Put 之间租约恰好过期：丢弃旧租约，重新申请一次
+		e.dropNodeLease(node.ID)
+		if leaseID, err = e.keepNodeLease(ctx, node.ID); err != nil {
+			return err
+		}
+		_, err = e.client.Put(ctx, key, string(bytes), clientv3.WithLease(leaseID))
+	}
+	return err
+}
+
+// UpdateNode 覆盖节点记录但保留原租约 (Key 不存在时返回 ErrNotFound，和 MemoryStore 一致)
+func (e *EtcdManager) UpdateNode(ctx context.Context, node *model.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	_, err = e.client.Put(ctx, NodeKeyPrefix+node.ID, string(bytes), clientv3.WithIgnoreLease())
+	if errors.Is(err, rpctypes.ErrKeyNotFound) {
+		return fmt.Errorf("node %s: %w", node.ID, ErrNotFound)
+	}
+	return err
 }
 
 func (e *EtcdManager) ListNodes(ctx context.Context) ([]*model.Node, error) {
@@ -121,10 +461,136 @@ func (e *EtcdManager) ListNodes(ctx context.Context) ([]*model.Node, error) {
 	return nodes, nil
 }
 
+// WatchNodes 监听 /titan/nodes/ 前缀的变化
+// 删除事件 (租约过期) 的 Value 为空，需要借助 PrevKV 拿到节点信息
+// 和 WatchJobs 一样断线后自动续上；无法恢复时推送一个 Err 非空的事件并关闭通道
+func (e *EtcdManager) WatchNodes(ctx context.Context) <-chan NodeEvent {
+	eventChan := make(chan NodeEvent)
+
+	go func() {
+		defer close(eventChan)
+
+		var nextRev int64
+		err := e.resumableWatch(ctx, "Node", NodeKeyPrefix, &nextRev, func(ev *clientv3.Event) error {
+			eventType := NodeUpdate
+			kv := ev.Kv
+			if ev.Type == clientv3.EventTypeDelete {
+				eventType = NodeDelete
+				if ev.PrevKv == nil {
+					return nil
+				}
+				kv = ev.PrevKv
+			}
+
+			var node model.Node
+			if err := json.Unmarshal(kv.Value, &node); err != nil {
+				log.Printf("[Etcd] Failed to unmarshal node: %v", err)
+				return nil
+			}
+
+			select {
+			case eventChan <- NodeEvent{Type: eventType, Node: &node}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case eventChan <- NodeEvent{Err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return eventChan
+}
+
+func (e *EtcdManager) WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk {
+	chunkChan := make(chan *model.LogChunk)
+
+	go func() {
+		defer close(chunkChan)
+		watchChan := e.client.Watch(clientv3.WithRequireLeader(ctx), logKeyPrefixFor(jobID), clientv3.WithPrefix())
+
+		for watchResp := range watchChan {
+			if err := watchResp.Err(); err != nil {
+				log.Printf("[Etcd] Log watch for job %s terminated: %v", jobID, err)
+				return
+			}
+			for _, ev := range watchResp.Events {
+				if ev.Type != clientv3.EventTypePut {
+					continue
+				}
+
+				var chunk model.LogChunk
+				if err := json.Unmarshal(ev.Kv.Value, &chunk); err != nil {
+					log.Printf("[Etcd] Failed to unmarshal log chunk: %v", err)
+					continue
+				}
+
+				select {
+				case chunkChan <- &chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return chunkChan
+}
+
 // ---------------------------------------------------------
 // 辅助方法 (Helpers)
 // ---------------------------------------------------------
 
+// keepNodeLease 续约节点已有的租约；没有或已过期则重新申请
+func (e *EtcdManager) keepNodeLease(ctx context.Context, nodeID string) (clientv3.LeaseID, error) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	if leaseID, ok := e.nodeLeases[nodeID]; ok {
+		if _, err := e.client.KeepAliveOnce(ctx, leaseID); err == nil {
+			return leaseID, nil
+		} else if !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return 0, err
+		}
+		delete(e.nodeLeases, nodeID)
+	}
+
+	resp, err := e.client.Grant(ctx, int64(NodeLeaseTTL.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	e.nodeLeases[nodeID] = resp.ID
+	return resp.ID, nil
+}
+
+// currentLogLease 返回当前写日志用的租约，超过 logLeaseRotation 就换一个新的
+func (e *EtcdManager) currentLogLease(ctx context.Context) (clientv3.LeaseID, error) {
+	e.logLeaseMu.Lock()
+	defer e.logLeaseMu.Unlock()
+
+	if e.logLease != 0 && time.Since(e.logLeaseAt) < logLeaseRotation {
+		return e.logLease, nil
+	}
+	resp, err := e.client.Grant(ctx, int64((logRetention + logLeaseRotation).Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	e.logLease = resp.ID
+	e.logLeaseAt = time.Now()
+	return resp.ID, nil
+}
+
+func (e *EtcdManager) dropNodeLease(nodeID string) {
+	e.leaseMu.Lock()
+	delete(e.nodeLeases, nodeID)
+	e.leaseMu.Unlock()
+}
+
 // putValue 封装通用的 JSON 序列化 + Put 操作
 func (e *EtcdManager) putValue(ctx context.Context, key string, val interface{}) error {
 	bytes, err := json.Marshal(val)
@@ -139,32 +605,58 @@ func (e *EtcdManager) putValue(ctx context.Context, key string, val interface{})
 // Log 相关实现
 // ---------------------------------------------------------
 
-func (e *EtcdManager) SaveJobLog(ctx context.Context, jobID string, logs string) error {
-	key := "/titan/logs/" + jobID
-	// 直接存字符串，或者像之前一样 json 包装一下也可以
-	// 这里为了简单，我们构造一个简单的结构体存进去，或者直接存字符串
-	// 为了复用 putValue，我们把日志包成一个对象
-	data := map[string]string{
-		"job_id":  jobID,
-		"content": logs,
+func (e *EtcdManager) AppendJobLog(ctx context.Context, chunk *model.LogChunk) error {
+	bytes, err := json.Marshal(chunk)
+	if err != nil {
+		return err
 	}
-	return e.putValue(ctx, key, data)
-}
-
-func (e *EtcdManager) GetJobLog(ctx context.Context, jobID string) (string, error) {
-	key := "/titan/logs/" + jobID
-	resp, err := e.client.Get(ctx, key)
+	leaseID, err := e.currentLogLease(ctx)
 	if err != nil {
-		return "", err
+		return err
 	}
-	if len(resp.Kvs) == 0 {
-		return "", fmt.Errorf("log not found for job %s", jobID)
+	_, err = e.client.Put(ctx, logChunkKey(chunk), string(bytes), clientv3.WithLease(leaseID))
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		// 租约被提前回收 (比如 Etcd 恢复了旧快照)，下次重新申请
+		e.logLeaseMu.Lock()
+		if e.logLease == leaseID {
+			e.logLease = 0
+		}
+		e.logLeaseMu.Unlock()
 	}
+	return err
+}
+
+func (e *EtcdManager) GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error) {
+	prefix := logKeyPrefixFor(jobID)
+	startKey := prefix
+	endKey := clientv3.GetPrefixRangeEnd(prefix)
+	var revision int64
+
+	// 日志可能很多，分批读取，所有批次固定在第一次读取时的 Revision 上
+	chunks := make([]*model.LogChunk, 0)
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(endKey), clientv3.WithLimit(listBatchSize)}
+		if revision > 0 {
+			opts = append(opts, clientv3.WithRev(revision))
+		}
+		resp, err := e.client.Get(ctx, startKey, opts...)
+		if err != nil {
+			return nil, err
+		}
+		revision = resp.Header.Revision
+
+		for _, kv := range resp.Kvs {
+			var chunk model.LogChunk
+			if err := json.Unmarshal(kv.Value, &chunk); err != nil {
+				log.Printf("Failed to unmarshal log chunk: %v", err)
+				continue
+			}
+			chunks = append(chunks, &chunk)
+		}
 
-	// 反序列化
-	var data map[string]string
-	if err := json.Unmarshal(resp.Kvs[0].Value, &data); err != nil {
-		return "", err
+		if !resp.More || len(resp.Kvs) == 0 {
+			return chunks, nil
+		}
+		startKey = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
 	}
-	return data["content"], nil
 }
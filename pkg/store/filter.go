@@ -0,0 +1,96 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// ErrInvalidContinue Continue Token 无法解析 (或者不是本 Store 签发的)
+var ErrInvalidContinue = errors.New("invalid continue token")
+
+// listBatchSize 分页查询时每次从存储层读取的条数 (过滤在客户端做，可能需要读多批)
+const listBatchSize = 200
+
+// JobFilter ListJobs 的查询条件，零值表示不过滤、不分页
+type JobFilter struct {
+	States     []model.JobState  // 任意一个匹配即可
+	NodeID     string            // 被分配到的节点
+	NamePrefix string            // 任务名前缀
+	Labels     map[string]string // 必须全部匹配
+
+	// 按提交时间过滤：[CreatedAfter, CreatedBefore)，零值表示不限制
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// 分页：Limit 为 0 表示返回全部；Continue 是上一页返回的 JobList.Continue
+	Limit    int
+	Continue string
+}
+
+// Match 判断任务是否满足过滤条件 (不包含分页)
+func (f *JobFilter) Match(job *model.Job) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.States) > 0 {
+		matched := false
+		for _, state := range f.States {
+			if job.Status.State == state {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.NodeID != "" && job.Status.NodeID != f.NodeID {
+		return false
+	}
+	if f.NamePrefix != "" && !strings.HasPrefix(job.Name, f.NamePrefix) {
+		return false
+	}
+	for k, v := range f.Labels {
+		if job.Labels[k] != v {
+			return false
+		}
+	}
+
+	if !f.CreatedAfter.IsZero() && job.CreateTime.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !job.CreateTime.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// continueToken 分页游标：上一页最后一个 Key + 第一页读取时的 Revision (保证翻页看到的是同一个快照)
+type continueToken struct {
+	LastKey  string `json:"k"`
+	Revision int64  `json:"r"`
+}
+
+func encodeContinue(lastKey string, revision int64) string {
+	bytes, _ := json.Marshal(continueToken{LastKey: lastKey, Revision: revision})
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func decodeContinue(token string) (*continueToken, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidContinue
+	}
+	var ct continueToken
+	if err := json.Unmarshal(bytes, &ct); err != nil || !strings.HasPrefix(ct.LastKey, JobKeyPrefix) {
+		return nil, ErrInvalidContinue
+	}
+	return &ct, nil
+}
@@ -2,9 +2,15 @@ package store
 
 import (
 	"context"
+	"time"
+
 	"titan/pkg/model"
 )
 
+// NodeLeaseTTL 节点 Key 绑定的租约时长
+// Worker 必须在这个时间内续约 (RegisterNode)，否则节点记录会被自动删除
+const NodeLeaseTTL = 15 * time.Second
+
 // JobEventType 定义监听事件类型
 type JobEventType int
 
@@ -17,8 +23,43 @@ const (
 // JobEvent 包装了 Etcd 中发生的事件
 // 调度器通过这个结构体知道有新任务来了
 type JobEvent struct {
-	Type JobEventType
-	Job  *model.Job
+	Type     JobEventType
+	Job      *model.Job
+	Revision int64 // 产生这个事件的 Store Revision
+
+	// 非 nil 表示 Watch 已经终止 (此时 Job 为 nil)，Channel 随后会被关闭
+	// errors.Is(Err, ErrCompacted) 时需要重新 List，再从新的 Revision 开始 Watch
+	Err error
+}
+
+// JobList ListJobs 的返回结果
+type JobList struct {
+	Jobs []*model.Job
+
+	// 读取时 Store 的全局 Revision
+	// 配合 WatchJobs(ctx, Revision+1) 使用，可以保证 List 和 Watch 之间不丢事件
+	Revision int64
+
+	// 非空表示还有下一页，放进 JobFilter.Continue 继续查询
+	Continue string
+}
+
+// NodeEventType 定义节点事件类型
+type NodeEventType int
+
+const (
+	NodeUpdate NodeEventType = iota // 注册 / 心跳 / 状态变化 (都是 Put)
+	NodeDelete                      // 租约过期或被删除
+)
+
+// NodeEvent 包装了节点的变化
+// 调度器通过它感知新节点加入、节点恢复，从而重新评估排队中的任务
+type NodeEvent struct {
+	Type NodeEventType
+	Node *model.Node
+
+	// 非 nil 表示 Watch 已经终止 (此时 Node 为 nil)，Channel 随后会被关闭，调用方重新 List 再 Watch
+	Err error
 }
 
 // Store 接口定义了系统对存储层的所有需求
@@ -26,25 +67,55 @@ type JobEvent struct {
 type Store interface {
 	// --- Job 相关 ---
 
-	// CreateJob 提交新任务
+	// CreateJob 提交新任务；同 ID 的任务已经存在时返回 ErrAlreadyExists，不会覆盖
+	// 成功后 job.ResourceVersion 会被更新为新版本
 	CreateJob(ctx context.Context, job *model.Job) error
 
+	// CreateJobs 原子地提交一组新任务 (按给定顺序写入)，要么全部写入，要么一个都不写入：
+	// 任何一个 ID 已经存在时返回 ErrAlreadyExists，requires 中的任务 (工作流之外的依赖) 不存在时返回 ErrNotFound
+	// 成功后每个 job.ResourceVersion 都会被更新为新版本
+	CreateJobs(ctx context.Context, jobs []*model.Job, requires []string) error
+
 	// GetJob 获取单个任务详情
 	GetJob(ctx context.Context, id string) (*model.Job, error)
 
+	// GetJobWithRevision 同 GetJob，另外返回读取时 Store 的全局 Revision
+	// 要 Watch 单个任务时用 WatchJobs(ctx, revision+1)，而不是任务自己的 ResourceVersion：
+	// 很久没有变化的任务 (比如长时间运行的任务)，它的 ResourceVersion 可能早已被压缩
+	GetJobWithRevision(ctx context.Context, id string) (*model.Job, int64, error)
+
+	// ListJobs 按条件分页查询任务 (filter 为 nil 表示返回全部)
+	ListJobs(ctx context.Context, filter *JobFilter) (*JobList, error)
+
 	// UpdateJob 更新任务状态 (调度器 Bind 时调用)
 	UpdateJob(ctx context.Context, job *model.Job) error
 
-	SaveJobLog(ctx context.Context, jobID string, logs string) error
-	GetJobLog(ctx context.Context, jobID string) (string, error)
+	// CompareAndSwapJob 乐观并发写入：仅当 job.ResourceVersion 与存储中的版本一致时才写入
+	// ResourceVersion 为 0 表示期望 Key 不存在；成功后 job.ResourceVersion 会被更新为新版本
+	// 版本不一致时返回 *ConflictError (errors.Is(err, ErrConflict) 为 true)
+	CompareAndSwapJob(ctx context.Context, job *model.Job) error
+
+	// DeleteJob 删除任务 (Watcher 会收到 JobDelete 事件)
+	DeleteJob(ctx context.Context, id string) error
+
 	// WatchJobs 监听任务变化 (返回一个只读通道)
-	WatchJobs(ctx context.Context) <-chan JobEvent
+	// fromRevision > 0 时从该 Revision 开始回放 (包含)，0 表示只监听之后的新变化
+	// 连接中断会自动续上；无法恢复时推送一个 Err 非空的事件并关闭通道
+	WatchJobs(ctx context.Context, fromRevision int64) <-chan JobEvent
 
 	// --- Node 相关 ---
 
-	// RegisterNode 节点注册 (Worker 启动时调用)
+	// RegisterNode 节点注册 / 心跳续约 (Worker 周期性调用)
+	// 节点 Key 绑定在 NodeLeaseTTL 租约上，Worker 宕机后会自动过期
 	RegisterNode(ctx context.Context, node *model.Node) error
 
-	// ListNodes 获取所有节点 (调度器 Filter 时调用)
+	// UpdateNode 更新已存在的节点记录，保留原有租约 (Master 标记 OFFLINE 时调用)
+	UpdateNode(ctx context.Context, node *model.Node) error
+
+	// ListNodes 获取所有节点 (调度器启动和节点 Watch 重建时全量同步)
 	ListNodes(ctx context.Context) ([]*model.Node, error)
+
+	// WatchNodes 监听节点变化 (返回一个只读通道)
+	// 连接中断会自动续上；无法恢复时推送一个 Err 非空的事件并关闭通道
+	WatchNodes(ctx context.Context) <-chan NodeEvent
 }
@@ -0,0 +1,589 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"titan/pkg/model"
+)
+
+// 编译期检查：MemoryStore 必须实现 Store 接口
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore 是 Store 接口的纯内存实现
+// 用途：单元测试 & 单进程 (All-in-one) 开发模式，无需启动 Etcd
+// 语义与 EtcdManager 保持一致：值以 JSON 形式保存 (读写互不共享指针)，
+// 每次写入都会递增全局 Revision，并按顺序推送给所有 Watcher
+type MemoryStore struct {
+	mu       sync.RWMutex
+	revision int64
+	kvs      map[string]memEntry // key -> JSON value (和 Etcd 一样存序列化后的数据)
+	watchers map[*memWatcher[JobEvent]]struct{}
+
+	// 最近的 Job 事件 (用于 WatchJobs 从指定 Revision 回放)，超过 historyLimit 的旧事件会被压缩掉
+	history           []memJobEvent
+	compactedRevision int64
+
+	nodeWatchers map[*memWatcher[NodeEvent]]struct{}
+	logWatchers  map[*memWatcher[*model.LogChunk]]string // -> 监听的 jobID
+
+	// 节点 Key 的过期时间 (模拟 Etcd 租约)，expiryTimer 在最早的过期时间触发，删除过期的节点
+	nodeExpiry  map[string]time.Time
+	expiryTimer *time.Timer
+}
+
+// NewMemoryStore 初始化内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		kvs:      make(map[string]memEntry),
+		watchers: make(map[*memWatcher[JobEvent]]struct{}),
+
+		nodeWatchers: make(map[*memWatcher[NodeEvent]]struct{}),
+		logWatchers:  make(map[*memWatcher[*model.LogChunk]]string),
+		nodeExpiry:   make(map[string]time.Time),
+	}
+}
+
+// ---------------------------------------------------------
+// Job 相关实现
+// ---------------------------------------------------------
+
+func (m *MemoryStore) CreateJob(ctx context.Context, job *model.Job) error {
+	if job.CreateTime.IsZero() {
+		job.CreateTime = time.Now()
+	}
+	job.ResourceVersion = 0
+	err := m.CompareAndSwapJob(ctx, job)
+	if IsConflict(err) {
+		return fmt.Errorf("job %s: %w", job.ID, ErrAlreadyExists)
+	}
+	return err
+}
+
+// CreateJobs 整批检查和写入都持有写锁，其他写入不会穿插进来
+func (m *MemoryStore) CreateJobs(ctx context.Context, jobs []*model.Job, requires []string) error {
+	now := time.Now()
+	values := make([][]byte, len(jobs))
+	for i, job := range jobs {
+		if job.CreateTime.IsZero() {
+			job.CreateTime = now
+		}
+		job.ResourceVersion = 0
+		bytes, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		values[i] = bytes
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range jobs {
+		if _, ok := m.kvs[JobKeyPrefix+job.ID]; ok {
+			return fmt.Errorf("job %s: %w", job.ID, ErrAlreadyExists)
+		}
+	}
+	for _, id := range requires {
+		if _, ok := m.kvs[JobKeyPrefix+id]; !ok {
+			return fmt.Errorf("job %s: %w", id, ErrNotFound)
+		}
+	}
+	for i, job := range jobs {
+		job.ResourceVersion = m.putLocked(JobKeyPrefix+job.ID, values[i])
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	job, _, err := m.GetJobWithRevision(ctx, id)
+	return job, err
+}
+
+func (m *MemoryStore) GetJobWithRevision(ctx context.Context, id string) (*model.Job, int64, error) {
+	m.mu.RLock()
+	entry, ok := m.kvs[JobKeyPrefix+id]
+	revision := m.revision
+	m.mu.RUnlock()
+	if !ok {
+		return nil, 0, fmt.Errorf("job %s: %w", id, ErrNotFound)
+	}
+	job, err := entry.job()
+	if err != nil {
+		return nil, 0, err
+	}
+	return job, revision, nil
+}
+
+// ListJobs 按条件分页查询任务
+// 内存实现没有多版本快照，翻页时看到的是最新数据 (Continue 只记录上一页最后一个 Key)
+func (m *MemoryStore) ListJobs(ctx context.Context, filter *JobFilter) (*JobList, error) {
+	if filter == nil {
+		filter = &JobFilter{}
+	}
+
+	afterKey := ""
+	if filter.Continue != "" {
+		ct, err := decodeContinue(filter.Continue)
+		if err != nil {
+			return nil, err
+		}
+		afterKey = ct.LastKey
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := &JobList{Jobs: make([]*model.Job, 0), Revision: m.revision}
+	keys := sortedKeys(m.kvs, JobKeyPrefix)
+	for i, key := range keys {
+		if key <= afterKey {
+			continue
+		}
+		job, err := m.kvs[key].job()
+		if err != nil {
+			log.Printf("Failed to unmarshal job: %v", err)
+			continue
+		}
+		if !filter.Match(job) {
+			continue
+		}
+
+		list.Jobs = append(list.Jobs, job)
+		if filter.Limit > 0 && len(list.Jobs) >= filter.Limit {
+			if i < len(keys)-1 {
+				list.Continue = encodeContinue(key, m.revision)
+			}
+			break
+		}
+	}
+	return list, nil
+}
+
+func (m *MemoryStore) UpdateJob(ctx context.Context, job *model.Job) error {
+	return m.putValue(JobKeyPrefix+job.ID, job)
+}
+
+// CompareAndSwapJob 仅当 job.ResourceVersion 等于当前版本时才写入
+func (m *MemoryStore) CompareAndSwapJob(ctx context.Context, job *model.Job) error {
+	key := JobKeyPrefix + job.ID
+	bytes, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 不存在的 Key 版本号视为 0 (和 Etcd 的 ModRevision 语义一致)
+	if m.kvs[key].modRevision != job.ResourceVersion {
+		return &ConflictError{Key: key, ResourceVersion: job.ResourceVersion}
+	}
+	job.ResourceVersion = m.putLocked(key, bytes)
+	return nil
+}
+
+// DeleteJob 删除任务，Watcher 会收到 JobDelete 事件
+func (m *MemoryStore) DeleteJob(ctx context.Context, id string) error {
+	key := JobKeyPrefix + id
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.kvs[key]
+	if !ok {
+		return nil
+	}
+	delete(m.kvs, key)
+	m.revision++
+	m.notifyLocked(JobDelete, entry.value)
+	return nil
+}
+
+// WatchJobs 返回按 Revision 顺序推送的任务事件
+// 和 Etcd 一样，Create 和 Update 都是 Put，统一上报为 JobUpdate
+func (m *MemoryStore) WatchJobs(ctx context.Context, fromRevision int64) <-chan JobEvent {
+	w := newMemWatcher[JobEvent]()
+
+	m.mu.Lock()
+	// 历史已经被压缩，无法保证不丢事件：和 Etcd 一样返回 ErrCompacted，让调用方重新 List
+	if fromRevision > 0 && fromRevision <= m.compactedRevision {
+		err := fmt.Errorf("%w: requested revision %d, compacted up to %d",
+			ErrCompacted, fromRevision, m.compactedRevision)
+		m.mu.Unlock()
+
+		eventChan := make(chan JobEvent, 1)
+		eventChan <- JobEvent{Err: err, Revision: fromRevision}
+		close(eventChan)
+		return eventChan
+	}
+
+	// 在同一把锁内回放历史并注册，保证回放和实时事件之间不丢不重
+	if fromRevision > 0 {
+		for _, ev := range m.history {
+			if ev.revision >= fromRevision {
+				m.deliverLocked(w, ev)
+			}
+		}
+	}
+	m.watchers[w] = struct{}{}
+	m.mu.Unlock()
+
+	return forwardEvents(ctx, w, func() {
+		m.mu.Lock()
+		delete(m.watchers, w)
+		m.mu.Unlock()
+	})
+}
+
+// ---------------------------------------------------------
+// Node 相关实现
+// ---------------------------------------------------------
+
+// RegisterNode 写入节点并续约 NodeLeaseTTL
+func (m *MemoryStore) RegisterNode(ctx context.Context, node *model.Node) error {
+	key := NodeKeyPrefix + node.ID
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.putLocked(key, bytes)
+	m.nodeExpiry[key] = time.Now().Add(NodeLeaseTTL)
+	m.scheduleExpiryLocked()
+	return nil
+}
+
+// UpdateNode 覆盖已存在的节点记录，不续约
+func (m *MemoryStore) UpdateNode(ctx context.Context, node *model.Node) error {
+	key := NodeKeyPrefix + node.ID
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expireNodesLocked(time.Now())
+	if _, ok := m.kvs[key]; !ok {
+		return fmt.Errorf("node %s: %w", node.ID, ErrNotFound)
+	}
+	m.putLocked(key, bytes)
+	return nil
+}
+
+func (m *MemoryStore) ListNodes(ctx context.Context) ([]*model.Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expireNodesLocked(time.Now())
+
+	nodes := make([]*model.Node, 0)
+	for _, key := range sortedKeys(m.kvs, NodeKeyPrefix) {
+		var node model.Node
+		if err := json.Unmarshal(m.kvs[key].value, &node); err != nil {
+			log.Printf("Failed to unmarshal node: %v", err)
+			continue
+		}
+		nodes = append(nodes, &node)
+	}
+	return nodes, nil
+}
+
+// WatchNodes 推送节点的注册/心跳/过期事件
+func (m *MemoryStore) WatchNodes(ctx context.Context) <-chan NodeEvent {
+	w := newMemWatcher[NodeEvent]()
+
+	m.mu.Lock()
+	m.nodeWatchers[w] = struct{}{}
+	m.mu.Unlock()
+
+	return forwardEvents(ctx, w, func() {
+		m.mu.Lock()
+		delete(m.nodeWatchers, w)
+		m.mu.Unlock()
+	})
+}
+
+// ---------------------------------------------------------
+// Log 相关实现
+// ---------------------------------------------------------
+
+func (m *MemoryStore) AppendJobLog(ctx context.Context, chunk *model.LogChunk) error {
+	return m.putValue(logChunkKey(chunk), chunk)
+}
+
+func (m *MemoryStore) GetJobLogs(ctx context.Context, jobID string) ([]*model.LogChunk, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	chunks := make([]*model.LogChunk, 0)
+	for _, key := range sortedKeys(m.kvs, logKeyPrefixFor(jobID)) {
+		var chunk model.LogChunk
+		if err := json.Unmarshal(m.kvs[key].value, &chunk); err != nil {
+			log.Printf("Failed to unmarshal log chunk: %v", err)
+			continue
+		}
+		chunks = append(chunks, &chunk)
+	}
+	return chunks, nil
+}
+
+func (m *MemoryStore) WatchJobLogs(ctx context.Context, jobID string) <-chan *model.LogChunk {
+	w := newMemWatcher[*model.LogChunk]()
+
+	m.mu.Lock()
+	m.logWatchers[w] = jobID
+	m.mu.Unlock()
+
+	return forwardEvents(ctx, w, func() {
+		m.mu.Lock()
+		delete(m.logWatchers, w)
+		m.mu.Unlock()
+	})
+}
+
+// ---------------------------------------------------------
+// 辅助方法 (Helpers)
+// ---------------------------------------------------------
+
+// putValue 序列化后写入，并在同一把锁内通知 Watcher，保证事件顺序与写入顺序一致
+func (m *MemoryStore) putValue(key string, val interface{}) error {
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.putLocked(key, bytes)
+	return nil
+}
+
+// putLocked 写入已序列化的值，返回新的 Revision (调用方必须持有写锁)
+func (m *MemoryStore) putLocked(key string, bytes []byte) int64 {
+	m.revision++
+	entry := memEntry{value: bytes, modRevision: m.revision}
+	m.kvs[key] = entry
+	switch {
+	case strings.HasPrefix(key, JobKeyPrefix):
+		m.notifyLocked(JobUpdate, bytes)
+	case strings.HasPrefix(key, NodeKeyPrefix):
+		m.notifyNodeLocked(NodeUpdate, entry)
+	case strings.HasPrefix(key, LogKeyPrefix):
+		m.notifyLogLocked(key, bytes)
+	}
+	return m.revision
+}
+
+// notifyLocked 记录当前 Revision 的 Job 事件并投递给所有 Watcher (调用方必须持有写锁)
+func (m *MemoryStore) notifyLocked(eventType JobEventType, value []byte) {
+	ev := memJobEvent{eventType: eventType, value: value, revision: m.revision}
+
+	m.history = append(m.history, ev)
+	if len(m.history) > historyLimit {
+		m.compactedRevision = m.history[0].revision
+		m.history = m.history[1:]
+	}
+
+	for w := range m.watchers {
+		m.deliverLocked(w, ev)
+	}
+}
+
+// deliverLocked 每个 Watcher 单独反序列化一份，避免消费者之间共享指针互相修改
+func (m *MemoryStore) deliverLocked(w *memWatcher[JobEvent], ev memJobEvent) {
+	var job model.Job
+	if err := json.Unmarshal(ev.value, &job); err != nil {
+		log.Printf("[Memory] Failed to unmarshal job: %v", err)
+		return
+	}
+	job.ResourceVersion = ev.revision
+	w.push(JobEvent{Type: ev.eventType, Job: &job, Revision: ev.revision})
+}
+
+// notifyNodeLocked 把 Node 事件投递给所有 Watcher (调用方必须持有写锁)
+func (m *MemoryStore) notifyNodeLocked(eventType NodeEventType, entry memEntry) {
+	for w := range m.nodeWatchers {
+		var node model.Node
+		if err := json.Unmarshal(entry.value, &node); err != nil {
+			log.Printf("[Memory] Failed to unmarshal node: %v", err)
+			continue
+		}
+		w.push(NodeEvent{Type: eventType, Node: &node})
+	}
+}
+
+// notifyLogLocked 把新写入的日志块推送给监听这个任务的 Watcher (调用方必须持有写锁)
+func (m *MemoryStore) notifyLogLocked(key string, value []byte) {
+	for w, jobID := range m.logWatchers {
+		if !strings.HasPrefix(key, logKeyPrefixFor(jobID)) {
+			continue
+		}
+		var chunk model.LogChunk
+		if err := json.Unmarshal(value, &chunk); err != nil {
+			log.Printf("[Memory] Failed to unmarshal log chunk: %v", err)
+			continue
+		}
+		w.push(&chunk)
+	}
+}
+
+// expireNodes 定时器触发：删除过期的节点 (WatchNodes 收到 NodeDelete)，再等下一个过期时间
+// 和 Etcd 租约一样，节点不再续约时不需要任何读写也会被删除
+func (m *MemoryStore) expireNodes() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireNodesLocked(time.Now())
+	m.scheduleExpiryLocked()
+}
+
+// scheduleExpiryLocked 把定时器设置到最早的过期时间 (调用方必须持有写锁)
+func (m *MemoryStore) scheduleExpiryLocked() {
+	var next time.Time
+	for _, expiry := range m.nodeExpiry {
+		if next.IsZero() || expiry.Before(next) {
+			next = expiry
+		}
+	}
+	if next.IsZero() {
+		return
+	}
+	if m.expiryTimer == nil {
+		m.expiryTimer = time.AfterFunc(time.Until(next), m.expireNodes)
+	} else {
+		m.expiryTimer.Reset(time.Until(next))
+	}
+}
+
+// expireNodesLocked 删除租约已经过期的节点 (调用方必须持有写锁)
+func (m *MemoryStore) expireNodesLocked(now time.Time) {
+	for key, expiry := range m.nodeExpiry {
+		if !now.Before(expiry) {
+			entry := m.kvs[key]
+			delete(m.kvs, key)
+			delete(m.nodeExpiry, key)
+			m.revision++
+			m.notifyNodeLocked(NodeDelete, entry)
+		}
+	}
+}
+
+// sortedKeys 返回指定前缀下按字典序排列的 Key (和 Etcd Range 的返回顺序一致)
+func sortedKeys(kvs map[string]memEntry, prefix string) []string {
+	keys := make([]string, 0)
+	for key := range kvs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// historyLimit 内存中保留的 Job 事件条数
+const historyLimit = 1000
+
+// memJobEvent 历史中的一条 Job 事件
+type memJobEvent struct {
+	eventType JobEventType
+	value     []byte
+	revision  int64
+}
+
+// memEntry 一个 Key 的值及其最后一次修改的 Revision
+type memEntry struct {
+	value       []byte
+	modRevision int64
+}
+
+// job 反序列化为 Job，并填充 ResourceVersion
+func (e memEntry) job() (*model.Job, error) {
+	var job model.Job
+	if err := json.Unmarshal(e.value, &job); err != nil {
+		return nil, err
+	}
+	job.ResourceVersion = e.modRevision
+	return &job, nil
+}
+
+// forwardEvents 把 Watcher 队列中的事件转发到对外的 channel，ctx 结束时注销并关闭 channel
+func forwardEvents[T any](ctx context.Context, w *memWatcher[T], unregister func()) <-chan T {
+	eventChan := make(chan T)
+	go func() {
+		defer close(eventChan)
+		defer unregister()
+
+		// ctx 结束时唤醒阻塞在 next() 上的协程
+		stop := context.AfterFunc(ctx, w.close)
+		defer stop()
+
+		for {
+			event, ok := w.next()
+			if !ok {
+				return
+			}
+			select {
+			case eventChan <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return eventChan
+}
+
+// memWatcher 是一个无界队列：写入方永不阻塞，慢消费者也不会丢事件
+type memWatcher[T any] struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []T
+	closed bool
+}
+
+func newMemWatcher[T any]() *memWatcher[T] {
+	w := &memWatcher[T]{}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+func (w *memWatcher[T]) push(event T) {
+	w.mu.Lock()
+	w.queue = append(w.queue, event)
+	w.mu.Unlock()
+	w.cond.Signal()
+}
+
+// next 阻塞直到有新事件或 Watcher 被关闭
+func (w *memWatcher[T]) next() (T, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(w.queue) == 0 && !w.closed {
+		w.cond.Wait()
+	}
+	if w.closed {
+		var zero T
+		return zero, false
+	}
+	event := w.queue[0]
+	w.queue = w.queue[1:]
+	return event, true
+}
+
+func (w *memWatcher[T]) close() {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	w.cond.Broadcast()
+}
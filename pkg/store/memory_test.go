@@ -0,0 +1,286 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"titan/pkg/model"
+)
+
+func TestMemoryStoreExpiresNodesWithoutReads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemoryStore()
+	events := m.WatchNodes(ctx)
+
+	for _, id := range []string{"node-1", "node-2"} {
+		if err := m.RegisterNode(ctx, &model.Node{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+		if ev := <-events; ev.Type != NodeUpdate || ev.Node.ID != id {
+			t.Fatalf("got event %v %s, want NodeUpdate %s", ev.Type, ev.Node.ID, id)
+		}
+	}
+
+	// 把 node-1 的租约缩短 (不用等 NodeLeaseTTL)；之后没有任何读写，定时器也要删除它
+	m.mu.Lock()
+	m.nodeExpiry[NodeKeyPrefix+"node-1"] = time.Now().Add(50 * time.Millisecond)
+	m.scheduleExpiryLocked()
+	m.mu.Unlock()
+
+	select {
+	case ev := <-events:
+		if ev.Type != NodeDelete || ev.Node.ID != "node-1" {
+			t.Fatalf("got event %v %s, want NodeDelete node-1", ev.Type, ev.Node.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for node-1 to expire")
+	}
+
+	nodes, err := m.ListNodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != "node-2" {
+		t.Fatalf("ListNodes() = %d nodes, want only node-2", len(nodes))
+	}
+}
+
+func newJob(id string, state model.JobState, nodeID string, labels map[string]string) *model.Job {
+	job := &model.Job{ID: id, Name: id, Labels: labels}
+	job.Status.State = state
+	job.Status.NodeID = nodeID
+	return job
+}
+
+func TestMemoryStoreCompareAndSwapJob(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+
+	job := newJob("a", model.JobPending, "", nil)
+	if err := m.CreateJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CreateJob(ctx, newJob("a", model.JobPending, "", nil)); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("CreateJob(existing) error = %v, want ErrAlreadyExists", err)
+	}
+
+	// 两个调度协程读到同一个版本，只有一个能绑定成功
+	first, _ := m.GetJob(ctx, "a")
+	second, _ := m.GetJob(ctx, "a")
+	first.Status.State, first.Status.NodeID = model.JobScheduled, "n1"
+	second.Status.State, second.Status.NodeID = model.JobScheduled, "n2"
+	if err := m.CompareAndSwapJob(ctx, first); err != nil {
+		t.Fatalf("first CompareAndSwapJob() error = %v", err)
+	}
+	if err := m.CompareAndSwapJob(ctx, second); !IsConflict(err) {
+		t.Fatalf("second CompareAndSwapJob() error = %v, want a conflict", err)
+	}
+
+	got, err := m.GetJob(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.NodeID != "n1" || got.ResourceVersion != first.ResourceVersion {
+		t.Fatalf("job = node %s version %d, want node n1 version %d", got.Status.NodeID, got.ResourceVersion, first.ResourceVersion)
+	}
+
+	// 读出来的是副本，修改不影响存储中的数据
+	got.Status.NodeID = "changed"
+	if again, _ := m.GetJob(ctx, "a"); again.Status.NodeID != "n1" {
+		t.Fatal("GetJob() returned a shared pointer")
+	}
+}
+
+func TestMemoryStoreListJobs(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	for _, job := range []*model.Job{
+		newJob("a-1", model.JobPending, "", map[string]string{"team": "x"}),
+		newJob("a-2", model.JobRunning, "n1", map[string]string{"team": "y"}),
+		newJob("b-1", model.JobRunning, "n2", map[string]string{"team": "x"}),
+		newJob("b-2", model.JobSuccess, "n1", nil),
+		newJob("b-3", model.JobFailed, "n1", map[string]string{"team": "x"}),
+	} {
+		if err := m.CreateJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter *JobFilter
+		want   []string
+	}{
+		{"all", nil, []string{"a-1", "a-2", "b-1", "b-2", "b-3"}},
+		{"states", &JobFilter{States: []model.JobState{model.JobRunning, model.JobFailed}}, []string{"a-2", "b-1", "b-3"}},
+		{"node", &JobFilter{NodeID: "n1"}, []string{"a-2", "b-2", "b-3"}},
+		{"name prefix", &JobFilter{NamePrefix: "b-"}, []string{"b-1", "b-2", "b-3"}},
+		{"labels", &JobFilter{Labels: map[string]string{"team": "x"}}, []string{"a-1", "b-1", "b-3"}},
+		{"combined", &JobFilter{NodeID: "n1", Labels: map[string]string{"team": "x"}}, []string{"b-3"}},
+		{"created before", &JobFilter{CreatedBefore: time.Now().Add(-time.Hour)}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := m.ListJobs(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(list.Jobs))
+			for _, job := range list.Jobs {
+				got = append(got, job.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListJobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 分页：每页 2 条，直到 Continue 为空
+	var pages [][]string
+	filter := &JobFilter{Limit: 2}
+	for {
+		list, err := m.ListJobs(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page []string
+		for _, job := range list.Jobs {
+			page = append(page, job.ID)
+		}
+		pages = append(pages, page)
+		if list.Continue == "" {
+			break
+		}
+		filter.Continue = list.Continue
+	}
+	if want := [][]string{{"a-1", "a-2"}, {"b-1", "b-2"}, {"b-3"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+
+	if _, err := m.ListJobs(ctx, &JobFilter{Continue: "garbage"}); !errors.Is(err, ErrInvalidContinue) {
+		t.Errorf("ListJobs(bad continue) error = %v, want ErrInvalidContinue", err)
+	}
+}
+
+func TestMemoryStoreWatchJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemoryStore()
+
+	if err := m.CreateJob(ctx, newJob("a", model.JobPending, "", nil)); err != nil {
+		t.Fatal(err)
+	}
+	list, _ := m.ListJobs(ctx, nil)
+	if err := m.CreateJob(ctx, newJob("b", model.JobPending, "", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 从 List 的下一个 Revision 开始：回放 List 之后的写入，再接上实时事件
+	events := m.WatchJobs(ctx, list.Revision+1)
+	if err := m.DeleteJob(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"update b", "delete a"}
+	for _, w := range want {
+		select {
+		case ev := <-events:
+			got := fmt.Sprintf("%s %s", map[JobEventType]string{JobUpdate: "update", JobDelete: "delete"}[ev.Type], ev.Job.ID)
+			if got != w {
+				t.Fatalf("event = %q, want %q", got, w)
+			}
+			if ev.Job.ResourceVersion != ev.Revision {
+				t.Errorf("event %q: job version %d, revision %d", got, ev.Job.ResourceVersion, ev.Revision)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", w)
+		}
+	}
+}
+
+func TestMemoryStoreWatchJobsCompacted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemoryStore()
+	for i := range historyLimit + 10 {
+		if err := m.CreateJob(ctx, newJob(fmt.Sprintf("job-%d", i), model.JobPending, "", nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 先收到 ErrCompacted，之后通道关闭
+	events := m.WatchJobs(ctx, 1)
+	ev, ok := <-events
+	if !ok || !errors.Is(ev.Err, ErrCompacted) {
+		t.Fatalf("WatchJobs(compacted revision) = %+v, want ErrCompacted", ev)
+	}
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("WatchJobs() kept sending after ErrCompacted")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchJobs() did not close after ErrCompacted")
+	}
+}
+
+func TestMemoryStoreLogs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMemoryStore()
+
+	chunk := func(jobID string, attempt int, seq int64) *model.LogChunk {
+		return &model.LogChunk{JobID: jobID, Attempt: attempt, Seq: seq, Lines: []model.LogLine{{Text: fmt.Sprintf("%d/%d", attempt, seq)}}}
+	}
+	watch := m.WatchJobLogs(ctx, "job-1")
+	for _, c := range []*model.LogChunk{chunk("job-1", 1, 0), chunk("job-10", 0, 0), chunk("job-1", 0, 1), chunk("job-1", 0, 0)} {
+		if err := m.AppendJobLog(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chunks, err := m.GetJobLogs(ctx, "job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range chunks {
+		got = append(got, c.Lines[0].Text)
+	}
+	if want := []string{"0/0", "0/1", "1/0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetJobLogs() = %v, want %v (sorted by attempt and seq, without job-10)", got, want)
+	}
+
+	for _, want := range []string{"1/0", "0/1", "0/0"} {
+		select {
+		case c := <-watch:
+			if c.JobID != "job-1" || c.Lines[0].Text != want {
+				t.Fatalf("watched %s %s, want job-1 %s", c.JobID, c.Lines[0].Text, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestMemoryStoreUpdateNode(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	if err := m.UpdateNode(ctx, &model.Node{ID: "n1"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("UpdateNode(unknown) error = %v, want ErrNotFound", err)
+	}
+	if err := m.RegisterNode(ctx, &model.Node{ID: "n1", Status: model.NodeReady}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.UpdateNode(ctx, &model.Node{ID: "n1", Status: model.NodeOffline}); err != nil {
+		t.Fatal(err)
+	}
+	nodes, _ := m.ListNodes(ctx)
+	if len(nodes) != 1 || nodes[0].Status != model.NodeOffline {
+		t.Fatalf("ListNodes() = %+v, want n1 OFFLINE", nodes)
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"titan/pkg/model"
+)
+
+// maxWorkflowOps 一个工作流最多涉及多少个任务 (提交的任务 + 工作流之外的依赖)
+// 整个工作流在一个 Etcd Txn 里写入，受 Etcd 单个 Txn 操作数的限制 (--max-txn-ops，默认 128)
+const maxWorkflowOps = 128
+
+// SubmitWorkflow 提交一组有依赖关系的任务
+// 提交前校验整个 DAG (依赖的任务必须存在、不能有环)，不通过时返回 *model.ValidationError，
+// 字段路径指向出问题的依赖 (jobs[i].dependencies[k])；
+// 然后按拓扑序用 CreateJobs 一次性写入 (调度器看到下游任务时上游任务已经存在)：
+// ID 已存在、或者外部依赖在这期间被删除时一个任务都不会写入，分别返回 ErrAlreadyExists / ErrNotFound
+func SubmitWorkflow(ctx context.Context, s Store, jobs []*model.Job) error {
+	inBatch := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		inBatch[job.ID] = true
+	}
+
+	// 工作流之外的依赖必须是已经提交过的任务
+	external := make(map[string]bool)
+	var requires []string
+	var unknown []*model.FieldError
+	for i, job := range jobs {
+		for k, dep := range job.Dependencies {
+			if inBatch[dep] {
+				continue
+			}
+			exists, checked := external[dep]
+			if !checked {
+				_, err := s.GetJob(ctx, dep)
+				switch {
+				case err == nil:
+					exists = true
+					requires = append(requires, dep)
+				case errors.Is(err, ErrNotFound):
+				default:
+					return fmt.Errorf("check dependency %s: %w", dep, err)
+				}
+				external[dep] = exists
+			}
+			if !exists {
+				unknown = append(unknown, &model.FieldError{
+					Field:  fmt.Sprintf("jobs[%d].dependencies[%d]", i, k),
+					Reason: fmt.Sprintf("job %q not found", dep),
+				})
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		return &model.ValidationError{Fields: unknown}
+	}
+	if n := len(jobs) + len(requires); n > maxWorkflowOps {
+		return &model.ValidationError{Fields: []*model.FieldError{{
+			Field:  "jobs",
+			Reason: fmt.Sprintf("workflow has %d jobs and %d external dependencies, at most %d in total are allowed", len(jobs), len(requires), maxWorkflowOps),
+		}}}
+	}
+
+	sorted, err := model.SortByDependencies(jobs, func(id string) bool { return external[id] })
+	if err != nil {
+		return err
+	}
+	return s.CreateJobs(ctx, sorted, requires)
+}
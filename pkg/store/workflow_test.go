@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"titan/pkg/model"
+)
+
+func workflowJob(id string, deps ...string) *model.Job {
+	job := &model.Job{ID: id, Type: model.JobTypeShell, Dependencies: deps}
+	job.Status.State = model.JobPending
+	return job
+}
+
+func TestSubmitWorkflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		jobs     []*model.Job
+		wantErr  error
+		wantIDs  []string // 成功时按写入顺序 (拓扑序) 排列的任务
+	}{
+		{
+			name:    "topological order",
+			jobs:    []*model.Job{workflowJob("c", "b"), workflowJob("b", "a"), workflowJob("a")},
+			wantIDs: []string{"a", "b", "c"},
+		},
+		{
+			name:     "external dependency",
+			existing: []string{"base"},
+			jobs:     []*model.Job{workflowJob("a", "base")},
+			wantIDs:  []string{"a"},
+		},
+		{
+			name:     "id already used",
+			existing: []string{"b"},
+			jobs:     []*model.Job{workflowJob("a"), workflowJob("b", "a")},
+			wantErr:  ErrAlreadyExists,
+		},
+		{
+			name:    "cycle",
+			jobs:    []*model.Job{workflowJob("a", "b"), workflowJob("b", "a")},
+			wantErr: &model.ValidationError{},
+		},
+		{
+			name:    "missing dependency",
+			jobs:    []*model.Job{workflowJob("a"), workflowJob("b", "missing")},
+			wantErr: &model.ValidationError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewMemoryStore()
+			for _, id := range tt.existing {
+				if err := s.CreateJob(ctx, workflowJob(id)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			list, _ := s.ListJobs(ctx, nil)
+			before := list.Revision
+
+			err := SubmitWorkflow(ctx, s, tt.jobs)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("SubmitWorkflow() error = %v", err)
+				}
+			case *model.ValidationError:
+				if !errors.As(err, &want) {
+					t.Fatalf("SubmitWorkflow() error = %v, want a ValidationError", err)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("SubmitWorkflow() error = %v, want %v", err, want)
+				}
+			}
+
+			// 失败时一个任务都不写入；成功时按拓扑序写入，版本号依次递增
+			list, _ = s.ListJobs(ctx, nil)
+			if tt.wantErr != nil {
+				if list.Revision != before || len(list.Jobs) != len(tt.existing) {
+					t.Fatalf("failed submission wrote jobs: %d jobs, revision %d -> %d", len(list.Jobs), before, list.Revision)
+				}
+				return
+			}
+			var prev int64
+			for _, id := range tt.wantIDs {
+				job, err := s.GetJob(ctx, id)
+				if err != nil {
+					t.Fatalf("GetJob(%s) error = %v", id, err)
+				}
+				if job.ResourceVersion <= prev {
+					t.Errorf("job %s written at revision %d, before its dependency (%d)", id, job.ResourceVersion, prev)
+				}
+				prev = job.ResourceVersion
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCreateJobsRequires(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	jobs := []*model.Job{workflowJob("a", "gone")}
+
+	// 外部依赖在校验之后被删除：整批都不写入
+	if err := s.CreateJobs(ctx, jobs, []string{"gone"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("CreateJobs() error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetJob(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("job a was written: %v", err)
+	}
+
+	if err := s.CreateJob(ctx, workflowJob("gone")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateJobs(ctx, jobs, []string{"gone"}); err != nil {
+		t.Fatalf("CreateJobs() error = %v", err)
+	}
+	if jobs[0].ResourceVersion == 0 {
+		t.Error("CreateJobs() did not set ResourceVersion")
+	}
+}